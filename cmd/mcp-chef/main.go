@@ -11,13 +11,28 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/go-chef/chef"
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/aknarts/chef-server-mcp/internal/chefapi"
 	"github.com/aknarts/chef-server-mcp/internal/config"
+	"github.com/aknarts/chef-server-mcp/internal/confirm"
+	"github.com/aknarts/chef-server-mcp/internal/constraint"
+	"github.com/aknarts/chef-server-mcp/internal/databag"
+	"github.com/aknarts/chef-server-mcp/internal/depsolve"
+	"github.com/aknarts/chef-server-mcp/internal/diff"
+	"github.com/aknarts/chef-server-mcp/internal/journal"
+	"github.com/aknarts/chef-server-mcp/internal/redact"
 	"github.com/aknarts/chef-server-mcp/internal/version"
 )
 
@@ -83,6 +98,212 @@ type GetUserOutput struct {
 	Organization string     `json:"organization"`
 }
 
+type ListClientsInput struct {
+	Organization *string `json:"organization,omitempty"`
+}
+type ClientSummary struct {
+	Name      string `json:"name"`
+	Validator bool   `json:"validator"`
+	HasNode   bool   `json:"hasNode" jsonschema:"False when no node with the same name exists (orphaned client)"`
+}
+type ListClientsOutput struct {
+	Clients      []ClientSummary `json:"clients"`
+	Organization string          `json:"organization"`
+}
+
+type GetClientInput struct {
+	Name         string  `json:"name"`
+	Organization *string `json:"organization,omitempty"`
+}
+type GetClientOutput struct {
+	Client       *chef.ApiClient   `json:"client"`
+	Keys         []chefapi.KeyInfo `json:"keys"`
+	HasNode      bool              `json:"hasNode"`
+	Organization string            `json:"organization"`
+}
+
+type ListGroupsInput struct {
+	Organization *string `json:"organization,omitempty"`
+}
+type ListGroupsOutput struct {
+	Groups       []string `json:"groups"`
+	Organization string   `json:"organization"`
+}
+
+type GetGroupInput struct {
+	Name         string  `json:"name"`
+	Organization *string `json:"organization,omitempty"`
+}
+type GetGroupOutput struct {
+	Group        *chef.Group         `json:"group"`
+	Members      []chefapi.Principal `json:"members" jsonschema:"Users and clients including those inherited through nested groups"`
+	Organization string              `json:"organization"`
+}
+
+type GetACLInput struct {
+	Kind         string  `json:"kind" jsonschema:"Object type: node, role, environment, dataBag, cookbook, cookbookArtifact, container, client, group, policy or policyGroup"`
+	Name         string  `json:"name"`
+	Organization *string `json:"organization,omitempty"`
+}
+type GetACLOutput struct {
+	ACL          chef.ACL `json:"acl"`
+	Kind         string   `json:"kind"`
+	Name         string   `json:"name"`
+	Organization string   `json:"organization"`
+}
+
+type EffectivePermissionsOutput struct {
+	Permissions  map[string][]chefapi.Principal `json:"permissions" jsonschema:"Principals per permission (create, read, update, delete, grant) and the group chain granting it"`
+	Kind         string                         `json:"kind"`
+	Name         string                         `json:"name"`
+	Organization string                         `json:"organization"`
+}
+
+type ListContainersInput struct {
+	Organization *string `json:"organization,omitempty"`
+}
+type ContainerWithACL struct {
+	Name  string   `json:"name"`
+	ACL   chef.ACL `json:"acl,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+type ListContainersOutput struct {
+	Containers   []ContainerWithACL `json:"containers"`
+	Organization string             `json:"organization"`
+}
+
+type GetContainerInput struct {
+	Name         string  `json:"name"`
+	Organization *string `json:"organization,omitempty"`
+}
+type GetContainerOutput struct {
+	Container    *chef.Container `json:"container"`
+	ACL          chef.ACL        `json:"acl" jsonschema:"Default ACL inherited by new objects created in this container"`
+	Organization string          `json:"organization"`
+}
+
+type ListPolicyGroupsInput struct {
+	Organization *string `json:"organization,omitempty"`
+}
+type ListPolicyGroupsOutput struct {
+	PolicyGroups map[string]chefapi.PolicyGroupPins `json:"policyGroups" jsonschema:"Policy group name to the revision id pinned for each policy"`
+	Organization string                             `json:"organization"`
+}
+
+type ListPoliciesInput struct {
+	Organization *string `json:"organization,omitempty"`
+}
+type ListPoliciesOutput struct {
+	Policies     []string `json:"policies"`
+	Organization string   `json:"organization"`
+}
+
+type ListPolicyRevisionsInput struct {
+	Name         string  `json:"name" jsonschema:"Policy name"`
+	Organization *string `json:"organization,omitempty"`
+}
+type PolicyRevisionEntry struct {
+	RevisionID   string   `json:"revisionId"`
+	PolicyGroups []string `json:"policyGroups,omitempty" jsonschema:"Policy groups currently pinning this revision"`
+}
+type ListPolicyRevisionsOutput struct {
+	Policy       string                `json:"policy"`
+	Revisions    []PolicyRevisionEntry `json:"revisions"`
+	Organization string                `json:"organization"`
+}
+
+type GetPolicyRevisionInput struct {
+	Name         string  `json:"name" jsonschema:"Policy name"`
+	RevisionID   *string `json:"revisionId,omitempty" jsonschema:"Revision id to fetch"`
+	PolicyGroup  *string `json:"policyGroup,omitempty" jsonschema:"Fetch the revision pinned in this policy group instead of an explicit revision id"`
+	Organization *string `json:"organization,omitempty"`
+}
+type GetPolicyRevisionOutput struct {
+	Revision     *chef.RevisionDetailsResponse `json:"revision"`
+	PolicyGroup  string                        `json:"policyGroup,omitempty"`
+	Organization string                        `json:"organization"`
+}
+
+type ListPolicyNodesInput struct {
+	PolicyGroup  *string `json:"policyGroup,omitempty"`
+	PolicyName   *string `json:"policyName,omitempty"`
+	Organization *string `json:"organization,omitempty"`
+}
+type ListPolicyNodesOutput struct {
+	Nodes        []string `json:"nodes"`
+	PolicyGroup  string   `json:"policyGroup,omitempty"`
+	PolicyName   string   `json:"policyName,omitempty"`
+	Organization string   `json:"organization"`
+}
+
+type ListUserKeysInput struct {
+	Name string `json:"name" jsonschema:"User name"`
+}
+type ListUserKeysOutput struct {
+	User string            `json:"user"`
+	Keys []chefapi.KeyInfo `json:"keys"`
+}
+
+type ListClientKeysInput struct {
+	Name         string  `json:"name" jsonschema:"Client name"`
+	Organization *string `json:"organization,omitempty"`
+}
+type ListClientKeysOutput struct {
+	Client       string            `json:"client"`
+	Keys         []chefapi.KeyInfo `json:"keys"`
+	Organization string            `json:"organization"`
+}
+
+type KeyExpirationReportInput struct {
+	Days         *int    `json:"days,omitempty" jsonschema:"Flag keys expiring within this many days (default 30)"`
+	IncludeValid bool    `json:"includeValid,omitempty" jsonschema:"Also list keys that are neither expired nor expiring"`
+	Organization *string `json:"organization,omitempty"`
+}
+type KeyReportEntry struct {
+	ActorType      string `json:"actorType"`
+	Actor          string `json:"actor"`
+	Key            string `json:"key"`
+	ExpirationDate string `json:"expirationDate"`
+	Status         string `json:"status" jsonschema:"expired, expiring, valid or never_expires"`
+	DaysRemaining  *int   `json:"daysRemaining,omitempty"`
+}
+type KeyExpirationReportOutput struct {
+	Days         int              `json:"days"`
+	Keys         []KeyReportEntry `json:"keys"`
+	Summary      map[string]int   `json:"summary" jsonschema:"Number of keys per status"`
+	Errors       []string         `json:"errors,omitempty"`
+	Organization string           `json:"organization"`
+}
+
+type ListInvitationsInput struct {
+	Organization *string `json:"organization,omitempty"`
+}
+type ListInvitationsOutput struct {
+	Invitations  []chef.Invite `json:"invitations"`
+	Organization string        `json:"organization"`
+}
+
+type ListUserOrganizationsInput struct {
+	Name string `json:"name" jsonschema:"User name"`
+}
+type ListUserOrganizationsOutput struct {
+	User          string              `json:"user"`
+	Organizations []chef.Organization `json:"organizations"`
+}
+
+type MembershipMatrixInput struct {
+	Organizations []string `json:"organizations,omitempty" jsonschema:"Organization names, aliases or groups; defaults to every accessible organization"`
+}
+type Membership struct {
+	Member bool `json:"member"`
+	Admin  bool `json:"admin"`
+}
+type MembershipMatrixOutput struct {
+	Organizations []string                         `json:"organizations"`
+	Users         map[string]map[string]Membership `json:"users" jsonschema:"User name to organization to membership"`
+	Errors        map[string]string                `json:"errors,omitempty" jsonschema:"Organizations that could not be read"`
+}
+
 type SearchInput struct {
 	Index        string  `json:"index"`
 	Query        string  `json:"query"`
@@ -109,6 +330,27 @@ type SearchJSONOutput struct {
 	Organization string            `json:"organization"`
 }
 
+type MultiOrgSearchInput struct {
+	Index         string   `json:"index"`
+	Query         string   `json:"query"`
+	Organizations []string `json:"organizations" jsonschema:"Organization names, aliases, groups from CHEF_ORG_GROUPS, or * for every accessible organization"`
+	MaxParallel   *int     `json:"maxParallel,omitempty" jsonschema:"Maximum concurrent organization queries (default 4)"`
+}
+type OrgSearchRow struct {
+	Organization string      `json:"organization"`
+	Row          interface{} `json:"row"`
+}
+type OrgSearchStatus struct {
+	Organization string `json:"organization"`
+	Total        int    `json:"total"`
+	Error        string `json:"error,omitempty"`
+}
+type MultiOrgSearchOutput struct {
+	Total         int               `json:"total"`
+	Rows          []OrgSearchRow    `json:"rows"`
+	Organizations []OrgSearchStatus `json:"organizations"`
+}
+
 // New tool types for additional Chef resources
 type GetOrganizationInput struct {
 	Organization *string `json:"organization,omitempty"`
@@ -118,6 +360,31 @@ type GetOrganizationOutput struct {
 	OrgName      string             `json:"orgName"`
 }
 
+type ListOrganizationsInput struct{}
+type OrganizationEntry struct {
+	Name     string   `json:"name"`
+	FullName string   `json:"fullName,omitempty"`
+	Guid     string   `json:"guid,omitempty"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Default  bool     `json:"default,omitempty"`
+}
+type ListOrganizationsOutput struct {
+	Organizations []OrganizationEntry `json:"organizations"`
+}
+
+type ServerStatusInput struct{}
+type ServerStatusOutput struct {
+	ServerURL        string                    `json:"serverUrl"`
+	LatencyMs        int64                     `json:"latencyMs" jsonschema:"Round-trip time of the /_status request"`
+	Status           *chef.Status              `json:"status,omitempty"`
+	StatusError      string                    `json:"statusError,omitempty"`
+	License          *chef.License             `json:"license,omitempty"`
+	LicenseError     string                    `json:"licenseError,omitempty"`
+	APIVersion       *chefapi.ServerAPIVersion `json:"apiVersion,omitempty"`
+	APIVersionError  string                    `json:"apiVersionError,omitempty"`
+	CredentialsValid *bool                     `json:"credentialsValid,omitempty" jsonschema:"False when the server rejected the signed request (401/403)"`
+}
+
 type ListCookbooksInput struct {
 	Organization *string `json:"organization,omitempty"`
 }
@@ -133,9 +400,51 @@ type GetCookbookInput struct {
 }
 type GetCookbookOutput struct {
 	Cookbook     *chef.Cookbook `json:"cookbook"`
+	Identifier   string         `json:"identifier,omitempty" jsonschema:"Cookbook artifact identifier (Policyfile cookbooks only)"`
 	Organization string         `json:"organization"`
 }
 
+type ListCookbookArtifactsInput struct {
+	Name         *string `json:"name,omitempty" jsonschema:"Only list artifacts of this cookbook"`
+	Organization *string `json:"organization,omitempty"`
+}
+type ListCookbookArtifactsOutput struct {
+	Artifacts    map[string][]string `json:"artifacts" jsonschema:"Cookbook name to artifact identifiers"`
+	Organization string              `json:"organization"`
+}
+
+type GetCookbookArtifactInput struct {
+	Name         string  `json:"name"`
+	Identifier   string  `json:"identifier"`
+	Organization *string `json:"organization,omitempty"`
+}
+
+type GetUniverseInput struct {
+	Names        []string `json:"names,omitempty" jsonschema:"Only return these cookbooks"`
+	Organization *string  `json:"organization,omitempty"`
+}
+type GetUniverseOutput struct {
+	Cookbooks    map[string]map[string]map[string]string `json:"cookbooks" jsonschema:"Cookbook name to version to dependency constraints"`
+	Organization string                                  `json:"organization"`
+}
+
+type FindCookbookDependentsInput struct {
+	Cookbook     string  `json:"cookbook" jsonschema:"Cookbook that others depend on"`
+	Constraint   *string `json:"constraint,omitempty" jsonschema:"Only report dependents declaring exactly this constraint, e.g. ~> 2.1"`
+	Version      *string `json:"version,omitempty" jsonschema:"Only report dependents whose constraint allows this version of the cookbook"`
+	Organization *string `json:"organization,omitempty"`
+}
+type CookbookDependent struct {
+	Cookbook   string `json:"cookbook"`
+	Version    string `json:"version"`
+	Constraint string `json:"constraint"`
+}
+type FindCookbookDependentsOutput struct {
+	Cookbook     string              `json:"cookbook"`
+	Dependents   []CookbookDependent `json:"dependents"`
+	Organization string              `json:"organization"`
+}
+
 type ListDataBagsInput struct {
 	Organization *string `json:"organization,omitempty"`
 }
@@ -158,11 +467,42 @@ type GetDataBagItemInput struct {
 	BagName      string  `json:"bagName"`
 	ItemName     string  `json:"itemName"`
 	Organization *string `json:"organization,omitempty"`
+	Decrypt      bool    `json:"decrypt,omitempty" jsonschema:"Decrypt an encrypted item (bag must be allowed by CHEF_DECRYPT_DATA_BAGS); values are redacted unless plaintext is set"`
+	Plaintext    bool    `json:"plaintext,omitempty" jsonschema:"Return decrypted values unredacted (requires CHEF_ALLOW_PLAINTEXT_SECRETS=true)"`
 }
 type GetDataBagItemOutput struct {
 	Item         *chef.DataBagItem `json:"item"`
 	DataBag      string            `json:"dataBag"`
 	Organization string            `json:"organization"`
+	Encrypted    bool              `json:"encrypted,omitempty"`
+	Decrypted    bool              `json:"decrypted,omitempty"`
+	Redacted     bool              `json:"redacted,omitempty"`
+}
+
+type GetVaultInfoInput struct {
+	Vault        string  `json:"vault" jsonschema:"Vault (data bag) name"`
+	Item         string  `json:"item" jsonschema:"Vault item name (without the _keys suffix)"`
+	Organization *string `json:"organization,omitempty"`
+}
+type GetVaultInfoOutput struct {
+	Vault        string                `json:"vault"`
+	Item         string                `json:"item"`
+	Metadata     databag.VaultMetadata `json:"metadata"`
+	CanDecrypt   bool                  `json:"canDecrypt" jsonschema:"True when the configured CHEF_USER is a vault admin"`
+	Organization string                `json:"organization"`
+}
+
+type GetVaultItemInput struct {
+	Vault        string  `json:"vault" jsonschema:"Vault (data bag) name"`
+	Item         string  `json:"item" jsonschema:"Vault item name"`
+	Plaintext    bool    `json:"plaintext,omitempty" jsonschema:"Return decrypted values unredacted (requires CHEF_ALLOW_PLAINTEXT_SECRETS=true)"`
+	Organization *string `json:"organization,omitempty"`
+}
+type GetVaultItemOutput struct {
+	Item         map[string]interface{} `json:"item"`
+	Vault        string                 `json:"vault"`
+	Redacted     bool                   `json:"redacted"`
+	Organization string                 `json:"organization"`
 }
 
 type ListEnvironmentsInput struct {
@@ -182,6 +522,248 @@ type GetEnvironmentOutput struct {
 	Organization string            `json:"organization"`
 }
 
+type DiffNodesInput struct {
+	NodeA         string   `json:"nodeA"`
+	NodeB         string   `json:"nodeB"`
+	OrganizationA *string  `json:"organizationA,omitempty"`
+	OrganizationB *string  `json:"organizationB,omitempty"`
+	IgnorePaths   []string `json:"ignorePaths,omitempty" jsonschema:"Attribute paths to skip (dot notation, * matches one segment); defaults to CHEF_DIFF_IGNORE_PATHS"`
+}
+type RunListDiff struct {
+	Added        []string `json:"added"`
+	Removed      []string `json:"removed"`
+	OrderChanged bool     `json:"orderChanged"`
+}
+type DiffNodesOutput struct {
+	NodeA         string                   `json:"nodeA"`
+	NodeB         string                   `json:"nodeB"`
+	OrganizationA string                   `json:"organizationA"`
+	OrganizationB string                   `json:"organizationB"`
+	Environment   *diff.Change             `json:"environment,omitempty"`
+	RunList       RunListDiff              `json:"runList"`
+	Attributes    map[string][]diff.Change `json:"attributes"`
+	IgnoredPaths  []string                 `json:"ignoredPaths"`
+}
+
+type DiffAcrossOrgsInput struct {
+	Kind          string  `json:"kind" jsonschema:"Object type: environment, role or dataBagItem"`
+	Name          string  `json:"name" jsonschema:"Environment, role or data bag item name"`
+	BagName       *string `json:"bagName,omitempty" jsonschema:"Data bag name (required for dataBagItem)"`
+	OrganizationA string  `json:"organizationA" jsonschema:"First organization name or alias"`
+	OrganizationB string  `json:"organizationB" jsonschema:"Second organization name or alias"`
+}
+type DiffAcrossOrgsOutput struct {
+	Kind             string                   `json:"kind"`
+	Name             string                   `json:"name"`
+	OrganizationA    string                   `json:"organizationA"`
+	OrganizationB    string                   `json:"organizationB"`
+	Identical        bool                     `json:"identical"`
+	RunList          *RunListDiff             `json:"runList,omitempty"`
+	EnvRunLists      map[string]RunListDiff   `json:"envRunLists,omitempty"`
+	CookbookVersions []diff.Change            `json:"cookbookVersions,omitempty"`
+	Attributes       map[string][]diff.Change `json:"attributes,omitempty"`
+	Other            []diff.Change            `json:"other,omitempty"`
+}
+
+// PlanStatus is the two-phase state shared by every write tool: the plan call returns a confirmation
+// bound to a hash of the plan, and the apply call must present it before anything changes
+type PlanStatus struct {
+	Confirmation string     `json:"confirmation,omitempty" jsonschema:"Pass back unchanged to apply this plan; single use"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	PlanHash     string     `json:"planHash"`
+	Applied      bool       `json:"applied"`
+}
+
+// UpdateNodeRunListInput edits a node run list
+type UpdateNodeRunListInput struct {
+	NodeName     string   `json:"nodeName"`
+	Action       string   `json:"action" jsonschema:"add, remove or replace"`
+	Items        []string `json:"items" jsonschema:"Run-list entries such as role[web] or recipe[nginx::default]; bare names are recipes"`
+	Position     *int     `json:"position,omitempty" jsonschema:"Zero-based index to insert added entries at (default: end of the run list)"`
+	Target       *string  `json:"target,omitempty" jsonschema:"Entry replaced in place by items; when omitted replace swaps the whole run list"`
+	DryRun       *bool    `json:"dryRun,omitempty" jsonschema:"Only plan the change (default true); set false together with the confirmation to apply"`
+	Confirmation *string  `json:"confirmation,omitempty" jsonschema:"Confirmation returned by the plan call; omit to plan without changing anything"`
+	Organization *string  `json:"organization,omitempty"`
+}
+type UpdateNodeRunListOutput struct {
+	NodeName     string `json:"nodeName"`
+	Organization string `json:"organization"`
+	Environment  string `json:"environment"`
+	DryRun       bool   `json:"dryRun"`
+	PlanStatus
+	Before        []string                 `json:"before"`
+	After         []string                 `json:"after"`
+	RunListDiff   RunListDiff              `json:"runListDiff"`
+	RecipesBefore []string                 `json:"recipesBefore,omitempty"`
+	RecipesAfter  []string                 `json:"recipesAfter,omitempty"`
+	RecipeDiff    *RunListDiff             `json:"recipeDiff,omitempty"`
+	Problems      []chefapi.RunListProblem `json:"problems,omitempty"`
+}
+
+// NodeTagsInput adds or removes node tags
+type NodeTagsInput struct {
+	NodeName     string   `json:"nodeName"`
+	Tags         []string `json:"tags"`
+	Confirmation *string  `json:"confirmation,omitempty" jsonschema:"Confirmation returned by the plan call; omit to plan without changing anything"`
+	Organization *string  `json:"organization,omitempty"`
+}
+type NodeTagsOutput struct {
+	NodeName     string   `json:"nodeName"`
+	Organization string   `json:"organization"`
+	Before       []string `json:"before"`
+	After        []string `json:"after"`
+	Changed      bool     `json:"changed"`
+	PlanStatus
+}
+
+// ListTagsInput counts tags across nodes
+type ListTagsInput struct {
+	Query        *string `json:"query,omitempty" jsonschema:"Node search query limiting the nodes counted (default *:*)"`
+	Organization *string `json:"organization,omitempty"`
+}
+type ListTagsOutput struct {
+	Organization string             `json:"organization"`
+	Query        string             `json:"query"`
+	NodeCount    int                `json:"nodeCount"`
+	Tags         []chefapi.TagCount `json:"tags"`
+}
+
+// CreateDataBagInput creates an empty data bag
+type CreateDataBagInput struct {
+	BagName      string  `json:"bagName"`
+	Confirmation *string `json:"confirmation,omitempty" jsonschema:"Confirmation returned by the plan call; omit to plan without changing anything"`
+	Organization *string `json:"organization,omitempty"`
+}
+type CreateDataBagOutput struct {
+	BagName      string `json:"bagName"`
+	Organization string `json:"organization"`
+	PlanStatus
+}
+
+// DataBagItemWriteInput creates or replaces a data bag item
+type DataBagItemWriteInput struct {
+	BagName      string                 `json:"bagName"`
+	ItemName     string                 `json:"itemName"`
+	Item         map[string]interface{} `json:"item" jsonschema:"Complete item content; id defaults to itemName and must match it"`
+	Confirmation *string                `json:"confirmation,omitempty" jsonschema:"Confirmation returned by the plan call; omit to plan without changing anything"`
+	Organization *string                `json:"organization,omitempty"`
+}
+type DataBagItemWriteOutput struct {
+	BagName      string `json:"bagName"`
+	ItemName     string `json:"itemName"`
+	Organization string `json:"organization"`
+	PlanStatus
+	Encrypted bool          `json:"encrypted"`
+	Schema    string        `json:"schema,omitempty" jsonschema:"Where the JSON Schema the item was validated against came from"`
+	Changes   []diff.Change `json:"changes"`
+}
+
+// SetEnvironmentCookbookPinInput pins a cookbook version constraint in an environment
+type SetEnvironmentCookbookPinInput struct {
+	Environment  string     `json:"environment"`
+	Cookbook     string     `json:"cookbook"`
+	Constraint   string     `json:"constraint" jsonschema:"Version constraint such as '= 1.2.3' or '~> 1.2'; a bare version means '='"`
+	RunLists     [][]string `json:"runLists,omitempty" jsonschema:"Run lists to depsolve; defaults to the most common run lists of nodes in the environment"`
+	MaxRunLists  int        `json:"maxRunLists,omitempty" jsonschema:"Number of node run lists sampled when runLists is omitted (default 10)"`
+	Confirmation *string    `json:"confirmation,omitempty" jsonschema:"Confirmation returned by the plan call; omit to plan without changing anything"`
+	Organization *string    `json:"organization,omitempty"`
+}
+
+// PinSolveResult is the depsolver outcome for one run list
+type PinSolveResult struct {
+	RunList []string `json:"runList"`
+	Nodes   int      `json:"nodes,omitempty" jsonschema:"Nodes in the environment using this run list"`
+	Solved  bool     `json:"solved"`
+	Version string   `json:"version,omitempty" jsonschema:"Version of the pinned cookbook selected for this run list"`
+	Error   string   `json:"error,omitempty"`
+}
+type SetEnvironmentCookbookPinOutput struct {
+	Environment      string           `json:"environment"`
+	Organization     string           `json:"organization"`
+	Cookbook         string           `json:"cookbook"`
+	Previous         string           `json:"previous,omitempty"`
+	Constraint       string           `json:"constraint"`
+	MatchingVersions []string         `json:"matchingVersions"`
+	Solves           bool             `json:"solves"`
+	RunLists         []PinSolveResult `json:"runLists"`
+	PlanStatus
+	ServerCheck []PinSolveResult `json:"serverCheck,omitempty" jsonschema:"Chef server depsolver results after the change was applied"`
+	RolledBack  bool             `json:"rolledBack,omitempty" jsonschema:"The server depsolver failed for a run list, so the previous constraint was restored"`
+}
+
+// RoleWriteInput is a role definition for createRole and updateRole
+type RoleWriteInput struct {
+	Name               string                 `json:"name"`
+	Description        *string                `json:"description,omitempty"`
+	RunList            []string               `json:"runList,omitempty" jsonschema:"Role run list; on update omit to keep the current one"`
+	EnvRunLists        map[string][]string    `json:"envRunLists,omitempty" jsonschema:"Per-environment run lists; on update omit to keep the current ones"`
+	DefaultAttributes  map[string]interface{} `json:"defaultAttributes,omitempty" jsonschema:"Replaces the current default attributes whole; on update omit to keep them. Redacted values are refused"`
+	OverrideAttributes map[string]interface{} `json:"overrideAttributes,omitempty" jsonschema:"Replaces the current override attributes whole; on update omit to keep them. Redacted values are refused"`
+	Confirmation       *string                `json:"confirmation,omitempty" jsonschema:"Confirmation returned by the plan call; omit to plan without changing anything"`
+	Organization       *string                `json:"organization,omitempty"`
+}
+type RoleWriteOutput struct {
+	Name         string `json:"name"`
+	Organization string `json:"organization"`
+	PlanStatus
+	Role     *chef.Role               `json:"role"`
+	Changes  []diff.Change            `json:"changes"`
+	Problems []chefapi.RunListProblem `json:"problems,omitempty"`
+}
+
+// DecommissionNodeInput previews or performs deletion of a node and its client
+type DecommissionNodeInput struct {
+	NodeName     string  `json:"nodeName"`
+	ClientName   *string `json:"clientName,omitempty" jsonschema:"API client to delete with the node (default: the node name)"`
+	Confirmation *string `json:"confirmation,omitempty" jsonschema:"Confirmation returned by the preview call; omit to preview"`
+	Archive      bool    `json:"archive,omitempty" jsonschema:"Save the node, client and ACL JSON under CHEF_ARCHIVE_DIR before deleting"`
+	Organization *string `json:"organization,omitempty"`
+}
+type DecommissionNodeOutput struct {
+	NodeName     string   `json:"nodeName"`
+	ClientName   string   `json:"clientName"`
+	Organization string   `json:"organization"`
+	Environment  string   `json:"environment"`
+	RunList      []string `json:"runList"`
+	PolicyGroup  string   `json:"policyGroup,omitempty"`
+	PolicyName   string   `json:"policyName,omitempty"`
+	ClientExists bool     `json:"clientExists"`
+	Validator    bool     `json:"validator,omitempty"`
+	ACL          chef.ACL `json:"acl,omitempty"`
+	Archive      bool     `json:"archive"`
+	ArchiveDir   string   `json:"archiveDir,omitempty" jsonschema:"Directory the node archive is written to before deleting"`
+	PlanStatus
+	ArchivePath   string `json:"archivePath,omitempty"`
+	NodeDeleted   bool   `json:"nodeDeleted"`
+	ClientDeleted bool   `json:"clientDeleted"`
+}
+
+// ListChangeJournalInput filters the change journal
+type ListChangeJournalInput struct {
+	Kind         *string `json:"kind,omitempty" jsonschema:"node, role, environment, dataBag, dataBagItem or client"`
+	Name         *string `json:"name,omitempty" jsonschema:"Object name; data bag items use bag/item"`
+	Limit        int     `json:"limit,omitempty" jsonschema:"Maximum number of entries, newest first (default 50)"`
+	Organization *string `json:"organization,omitempty" jsonschema:"Organization to list; defaults to CHEF_DEFAULT_ORG, or every organization when unset"`
+}
+type ListChangeJournalOutput struct {
+	Directory string          `json:"directory"`
+	Entries   []journal.Entry `json:"entries"`
+}
+
+// RevertChangeInput restores the object snapshot stored in a journal entry
+type RevertChangeInput struct {
+	EntryID      string  `json:"entryId"`
+	Confirmation *string `json:"confirmation,omitempty" jsonschema:"Confirmation returned by the plan call; omit to plan without changing anything"`
+	Organization *string `json:"organization,omitempty"`
+}
+type RevertChangeOutput struct {
+	Entry        journal.Entry `json:"entry"`
+	Organization string        `json:"organization"`
+	Exists       bool          `json:"exists" jsonschema:"Whether the object currently exists; if not it is re-created"`
+	Changes      []diff.Change `json:"changes" jsonschema:"Differences from the current object to the snapshot being restored"`
+	PlanStatus
+}
+
 func main() {
 	log.SetOutput(os.Stderr)
 	cfg := config.LoadFromEnv()
@@ -197,14 +779,43 @@ func main() {
 		log.Printf("Warning: CHEF_DEFAULT_ORG not set. Organization must be specified in each request.")
 	}
 
+	if cfg.Mode != config.ModeReadOnly && cfg.Mode != config.ModeWrite {
+		log.Fatalf("invalid CHEF_MCP_MODE '%s': expected '%s' or '%s'", cfg.Mode, config.ModeReadOnly, config.ModeWrite)
+	}
+
 	chefClient, err := chefapi.NewChefAPI(cfg.ChefUser, cfg.ChefKeyPath, cfg.ChefServerURL)
 	if err != nil {
 		log.Fatalf("failed to init Chef API client: %v", err)
 	}
+	// The API refuses mutating requests on its own unless write mode is enabled
+	chefClient.ReadOnly = !cfg.WriteEnabled()
+	log.Printf("access mode: %s", cfg.Mode)
+
+	// Every change is preceded by a journal snapshot, so write mode needs a usable journal
+	if cfg.JournalDir != "" {
+		j, err := journal.New(cfg.JournalDir)
+		if err != nil {
+			log.Printf("Warning: change journal unavailable: %v", err)
+		} else {
+			chefClient.Journal = j
+		}
+	}
+	if cfg.WriteEnabled() && chefClient.Journal == nil {
+		log.Fatalf("write mode requires a change journal: set CHEF_JOURNAL_DIR to a writable directory")
+	}
 
 	impl := &mcp.Implementation{Name: "chef-server-mcp", Version: version.Version}
 	server := mcp.NewServer(impl, nil)
 
+	// Redact secrets from every tool result unless explicitly disabled
+	if !cfg.RedactDisabled {
+		engine, err := redact.New(redact.Options{KeyPatterns: cfg.RedactKeys, Paths: cfg.RedactPaths, Detectors: cfg.RedactDetectors})
+		if err != nil {
+			log.Fatalf("invalid redaction configuration: %v", err)
+		}
+		server.AddReceivingMiddleware(redactMiddleware(engine))
+	}
+
 	needAPI := func() (*chefapi.ChefAPI, error) {
 		if chefClient == nil {
 			return nil, errors.New("Chef API client not initialized")
@@ -220,10 +831,25 @@ func main() {
 		return *orgPtr
 	}
 
+	// Expand organization names, aliases and groups; "*" selects every accessible organization
+	resolveOrgList := func(api *chefapi.ChefAPI, inputs []string) ([]string, error) {
+		for _, o := range inputs {
+			if o == "*" {
+				orgs, err := api.ListAccessibleOrganizations()
+				if err != nil {
+					return nil, fmt.Errorf("list accessible organizations: %w", err)
+				}
+				return orgs, nil
+			}
+		}
+		return cfg.ResolveOrganizations(inputs), nil
+	}
+
 	// listNodes tool (API only) - now supports organization parameter
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "listNodes",
 		Description: "List Chef node names (Chef API) - optionally specify organization",
+		Annotations: readOnlyTool,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, in ListNodesInputWithOrg) (*mcp.CallToolResult, ListNodesOutputWithOrg, error) {
 		api, err := needAPI()
 		if err != nil {
@@ -244,7 +870,7 @@ func main() {
 	})
 
 	// getNode
-	mcp.AddTool(server, &mcp.Tool{Name: "getNode", Description: "Get a single Chef node by name - optionally specify organization"},
+	mcp.AddTool(server, &mcp.Tool{Name: "getNode", Description: "Get a single Chef node by name - optionally specify organization", Annotations: readOnlyTool},
 		func(ctx context.Context, req *mcp.CallToolRequest, in GetNodeInput) (*mcp.CallToolResult, GetNodeOutput, error) {
 			api, err := needAPI()
 			if err != nil {
@@ -265,7 +891,7 @@ func main() {
 		})
 
 	// listRoles
-	mcp.AddTool(server, &mcp.Tool{Name: "listRoles", Description: "List Chef role names - optionally specify organization"},
+	mcp.AddTool(server, &mcp.Tool{Name: "listRoles", Description: "List Chef role names - optionally specify organization", Annotations: readOnlyTool},
 		func(ctx context.Context, req *mcp.CallToolRequest, in ListRolesInput) (*mcp.CallToolResult, ListRolesOutput, error) {
 			api, err := needAPI()
 			if err != nil {
@@ -286,7 +912,7 @@ func main() {
 		})
 
 	// getRole
-	mcp.AddTool(server, &mcp.Tool{Name: "getRole", Description: "Get a single Chef role by name - optionally specify organization"},
+	mcp.AddTool(server, &mcp.Tool{Name: "getRole", Description: "Get a single Chef role by name - optionally specify organization", Annotations: readOnlyTool},
 		func(ctx context.Context, req *mcp.CallToolRequest, in GetRoleInput) (*mcp.CallToolResult, GetRoleOutput, error) {
 			api, err := needAPI()
 			if err != nil {
@@ -307,7 +933,7 @@ func main() {
 		})
 
 	// listUsers
-	mcp.AddTool(server, &mcp.Tool{Name: "listUsers", Description: "List Chef user names - optionally specify organization"},
+	mcp.AddTool(server, &mcp.Tool{Name: "listUsers", Description: "List Chef user names - optionally specify organization", Annotations: readOnlyTool},
 		func(ctx context.Context, req *mcp.CallToolRequest, in ListUsersInput) (*mcp.CallToolResult, ListUsersOutput, error) {
 			api, err := needAPI()
 			if err != nil {
@@ -328,7 +954,7 @@ func main() {
 		})
 
 	// getUser
-	mcp.AddTool(server, &mcp.Tool{Name: "getUser", Description: "Get a single Chef user by name - optionally specify organization"},
+	mcp.AddTool(server, &mcp.Tool{Name: "getUser", Description: "Get a single Chef user by name - optionally specify organization", Annotations: readOnlyTool},
 		func(ctx context.Context, req *mcp.CallToolRequest, in GetUserInput) (*mcp.CallToolResult, GetUserOutput, error) {
 			api, err := needAPI()
 			if err != nil {
@@ -348,148 +974,947 @@ func main() {
 			return nil, GetUserOutput{User: u, Organization: org}, nil
 		})
 
-	// search
-	mcp.AddTool(server, &mcp.Tool{Name: "search", Description: "Execute a Chef search and return decoded rows - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in SearchInput) (*mcp.CallToolResult, SearchOutput, error) {
+	// listClients
+	mcp.AddTool(server, &mcp.Tool{Name: "listClients", Description: "List Chef API clients with validator flag and whether a matching node exists - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListClientsInput) (*mcp.CallToolResult, ListClientsOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, SearchOutput{}, err
+				return nil, ListClientsOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, SearchOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				return nil, ListClientsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
 			}
 
-			res, err := api.Search(in.Index, in.Query, org)
+			names, err := api.ListClients(org)
 			if err != nil {
-				return nil, SearchOutput{}, err
+				return nil, ListClientsOutput{}, err
 			}
-			return nil, SearchOutput{Total: res.Total, Start: res.Start, Rows: res.Rows, Organization: org}, nil
+			validators, err := api.ListValidatorClients(org)
+			if err != nil {
+				return nil, ListClientsOutput{}, err
+			}
+			nodes, err := api.ListNodes(org)
+			if err != nil {
+				return nil, ListClientsOutput{}, err
+			}
+			nodeSet := make(map[string]bool, len(nodes))
+			for _, n := range nodes {
+				nodeSet[n] = true
+			}
+
+			clients := make([]ClientSummary, 0, len(names))
+			for _, name := range names {
+				clients = append(clients, ClientSummary{Name: name, Validator: validators[name], HasNode: nodeSet[name]})
+			}
+			return nil, ListClientsOutput{Clients: clients, Organization: org}, nil
 		})
 
-	// searchJSON
-	mcp.AddTool(server, &mcp.Tool{Name: "searchJSON", Description: "Execute a Chef search and return raw JSON rows - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in SearchJSONInput) (*mcp.CallToolResult, SearchJSONOutput, error) {
+	// getClient
+	mcp.AddTool(server, &mcp.Tool{Name: "getClient", Description: "Get a Chef API client with public key metadata and matching node status - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetClientInput) (*mcp.CallToolResult, GetClientOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, SearchJSONOutput{}, err
+				return nil, GetClientOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, SearchJSONOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				return nil, GetClientOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
 			}
 
-			res, err := api.SearchJSON(in.Index, in.Query, org)
+			c, err := api.GetClient(in.Name, org)
 			if err != nil {
-				return nil, SearchJSONOutput{}, err
+				return nil, GetClientOutput{}, err
 			}
-			rows := make([]json.RawMessage, 0, len(res.Rows))
-			for _, r := range res.Rows { // marshal each row back to raw JSON blob
-				b, err := json.Marshal(r)
-				if err != nil {
-					return nil, SearchJSONOutput{}, err
-				}
-				rows = append(rows, b)
+			keys, err := api.ListClientKeys(in.Name, org)
+			if err != nil {
+				return nil, GetClientOutput{}, err
 			}
-			return nil, SearchJSONOutput{Total: res.Total, Start: res.Start, Rows: rows, Organization: org}, nil
+			hasNode, err := api.NodeExists(in.Name, org)
+			if err != nil {
+				return nil, GetClientOutput{}, err
+			}
+			return nil, GetClientOutput{Client: c, Keys: keys, HasNode: hasNode, Organization: org}, nil
 		})
 
-	// getOrganization
-	mcp.AddTool(server, &mcp.Tool{Name: "getOrganization", Description: "Get organization details - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in GetOrganizationInput) (*mcp.CallToolResult, GetOrganizationOutput, error) {
+	// listGroups
+	mcp.AddTool(server, &mcp.Tool{Name: "listGroups", Description: "List Chef groups - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListGroupsInput) (*mcp.CallToolResult, ListGroupsOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, GetOrganizationOutput{}, err
+				return nil, ListGroupsOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, GetOrganizationOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				return nil, ListGroupsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
 			}
 
-			orgDetails, err := api.GetOrganization(org)
+			groups, err := api.ListGroups(org)
 			if err != nil {
-				return nil, GetOrganizationOutput{}, err
+				return nil, ListGroupsOutput{}, err
 			}
-			return nil, GetOrganizationOutput{Organization: orgDetails, OrgName: org}, nil
+			return nil, ListGroupsOutput{Groups: groups, Organization: org}, nil
 		})
 
-	// listCookbooks
-	mcp.AddTool(server, &mcp.Tool{Name: "listCookbooks", Description: "List Chef cookbooks and their versions - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in ListCookbooksInput) (*mcp.CallToolResult, ListCookbooksOutput, error) {
+	// getGroup
+	mcp.AddTool(server, &mcp.Tool{Name: "getGroup", Description: "Get a Chef group with its direct members and all users and clients inherited through nested groups - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetGroupInput) (*mcp.CallToolResult, GetGroupOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, ListCookbooksOutput{}, err
+				return nil, GetGroupOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, ListCookbooksOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				return nil, GetGroupOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
 			}
 
-			cookbooks, err := api.ListCookbooks(org)
+			g, err := api.GetGroup(in.Name, org)
 			if err != nil {
-				return nil, ListCookbooksOutput{}, err
+				return nil, GetGroupOutput{}, err
 			}
-			return nil, ListCookbooksOutput{Cookbooks: cookbooks, Organization: org}, nil
+			members, err := api.ExpandGroup(in.Name, org)
+			if err != nil {
+				return nil, GetGroupOutput{}, err
+			}
+			return nil, GetGroupOutput{Group: g, Members: members, Organization: org}, nil
 		})
 
-	// getCookbook
-	mcp.AddTool(server, &mcp.Tool{Name: "getCookbook", Description: "Get a Chef cookbook by name and version (defaults to _latest) - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in GetCookbookInput) (*mcp.CallToolResult, GetCookbookOutput, error) {
+	// getACL
+	mcp.AddTool(server, &mcp.Tool{Name: "getACL", Description: "Get the ACL of a Chef object (node, role, environment, dataBag, cookbook, container, ...) - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetACLInput) (*mcp.CallToolResult, GetACLOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, GetCookbookOutput{}, err
+				return nil, GetACLOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, GetCookbookOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
-			}
-
-			version := "_latest"
-			if in.Version != nil && *in.Version != "" {
-				version = *in.Version
+				return nil, GetACLOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
 			}
 
-			cookbook, err := api.GetCookbook(in.Name, version, org)
+			acl, err := api.GetACL(in.Kind, in.Name, org)
 			if err != nil {
-				return nil, GetCookbookOutput{}, err
+				return nil, GetACLOutput{}, err
 			}
-			return nil, GetCookbookOutput{Cookbook: cookbook, Organization: org}, nil
+			return nil, GetACLOutput{ACL: acl, Kind: in.Kind, Name: in.Name, Organization: org}, nil
 		})
 
-	// listDataBags
-	mcp.AddTool(server, &mcp.Tool{Name: "listDataBags", Description: "List Chef data bags - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in ListDataBagsInput) (*mcp.CallToolResult, ListDataBagsOutput, error) {
+	// effectivePermissions
+	mcp.AddTool(server, &mcp.Tool{Name: "effectivePermissions", Description: "Show which users and clients can create/read/update/delete/grant a Chef object and through which group - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetACLInput) (*mcp.CallToolResult, EffectivePermissionsOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, ListDataBagsOutput{}, err
+				return nil, EffectivePermissionsOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, ListDataBagsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				return nil, EffectivePermissionsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
 			}
 
-			dataBags, err := api.ListDataBags(org)
+			perms, err := api.EffectivePermissions(in.Kind, in.Name, org)
 			if err != nil {
-				return nil, ListDataBagsOutput{}, err
+				return nil, EffectivePermissionsOutput{}, err
 			}
-			return nil, ListDataBagsOutput{DataBags: dataBags, Organization: org}, nil
+			return nil, EffectivePermissionsOutput{Permissions: perms, Kind: in.Kind, Name: in.Name, Organization: org}, nil
 		})
 
-	// listDataBagItems
-	mcp.AddTool(server, &mcp.Tool{Name: "listDataBagItems", Description: "List items in a Chef data bag - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in ListDataBagItemsInput) (*mcp.CallToolResult, ListDataBagItemsOutput, error) {
+	// listContainers
+	mcp.AddTool(server, &mcp.Tool{Name: "listContainers", Description: "List Chef containers with the default ACL each applies to new objects - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListContainersInput) (*mcp.CallToolResult, ListContainersOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListContainersOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListContainersOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			names, err := api.ListContainers(org)
+			if err != nil {
+				return nil, ListContainersOutput{}, err
+			}
+			containers := make([]ContainerWithACL, 0, len(names))
+			for _, name := range names {
+				// An unreadable ACL should not hide the rest of the listing
+				entry := ContainerWithACL{Name: name}
+				acl, err := api.GetACL("container", name, org)
+				if err != nil {
+					entry.Error = err.Error()
+				} else {
+					entry.ACL = acl
+				}
+				containers = append(containers, entry)
+			}
+			return nil, ListContainersOutput{Containers: containers, Organization: org}, nil
+		})
+
+	// getContainer
+	mcp.AddTool(server, &mcp.Tool{Name: "getContainer", Description: "Get a Chef container and its ACL - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetContainerInput) (*mcp.CallToolResult, GetContainerOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetContainerOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetContainerOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			c, err := api.GetContainer(in.Name, org)
+			if err != nil {
+				return nil, GetContainerOutput{}, err
+			}
+			acl, err := api.GetACL("container", in.Name, org)
+			if err != nil {
+				return nil, GetContainerOutput{}, err
+			}
+			return nil, GetContainerOutput{Container: c, ACL: acl, Organization: org}, nil
+		})
+
+	// listPolicyGroups
+	mcp.AddTool(server, &mcp.Tool{Name: "listPolicyGroups", Description: "List Policyfile policy groups and the revision each pins per policy - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListPolicyGroupsInput) (*mcp.CallToolResult, ListPolicyGroupsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListPolicyGroupsOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListPolicyGroupsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			groups, err := api.ListPolicyGroups(org)
+			if err != nil {
+				return nil, ListPolicyGroupsOutput{}, err
+			}
+			return nil, ListPolicyGroupsOutput{PolicyGroups: groups, Organization: org}, nil
+		})
+
+	// listPolicies
+	mcp.AddTool(server, &mcp.Tool{Name: "listPolicies", Description: "List Policyfile policy names - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListPoliciesInput) (*mcp.CallToolResult, ListPoliciesOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListPoliciesOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListPoliciesOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			policies, err := api.ListPolicies(org)
+			if err != nil {
+				return nil, ListPoliciesOutput{}, err
+			}
+			return nil, ListPoliciesOutput{Policies: policies, Organization: org}, nil
+		})
+
+	// listPolicyRevisions
+	mcp.AddTool(server, &mcp.Tool{Name: "listPolicyRevisions", Description: "List revisions of a Policyfile policy and which policy groups pin each - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListPolicyRevisionsInput) (*mcp.CallToolResult, ListPolicyRevisionsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListPolicyRevisionsOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListPolicyRevisionsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			revisions, err := api.ListPolicyRevisions(in.Name, org)
+			if err != nil {
+				return nil, ListPolicyRevisionsOutput{}, err
+			}
+			groups, err := api.ListPolicyGroups(org)
+			if err != nil {
+				return nil, ListPolicyRevisionsOutput{}, err
+			}
+			pinnedBy := make(map[string][]string)
+			for group, pins := range groups {
+				if rev, ok := pins[in.Name]; ok {
+					pinnedBy[rev] = append(pinnedBy[rev], group)
+				}
+			}
+
+			entries := make([]PolicyRevisionEntry, 0, len(revisions))
+			for _, rev := range revisions {
+				g := pinnedBy[rev]
+				sort.Strings(g)
+				entries = append(entries, PolicyRevisionEntry{RevisionID: rev, PolicyGroups: g})
+			}
+			return nil, ListPolicyRevisionsOutput{Policy: in.Name, Revisions: entries, Organization: org}, nil
+		})
+
+	// getPolicyRevision
+	mcp.AddTool(server, &mcp.Tool{Name: "getPolicyRevision", Description: "Get a Policyfile revision lock (run list, cookbook locks, attributes) by revision id or policy group - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetPolicyRevisionInput) (*mcp.CallToolResult, GetPolicyRevisionOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetPolicyRevisionOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetPolicyRevisionOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			revision := getOrgString(in.RevisionID)
+			group := getOrgString(in.PolicyGroup)
+			var rev *chef.RevisionDetailsResponse
+			switch {
+			case revision != "":
+				rev, err = api.GetPolicyRevision(in.Name, revision, org)
+			case group != "":
+				rev, err = api.GetPolicyGroupPolicy(group, in.Name, org)
+			default:
+				return nil, GetPolicyRevisionOutput{}, fmt.Errorf("revisionId or policyGroup must be specified")
+			}
+			if err != nil {
+				return nil, GetPolicyRevisionOutput{}, err
+			}
+			return nil, GetPolicyRevisionOutput{Revision: rev, PolicyGroup: group, Organization: org}, nil
+		})
+
+	// listPolicyNodes
+	mcp.AddTool(server, &mcp.Tool{Name: "listPolicyNodes", Description: "List nodes by policy_group and/or policy_name - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListPolicyNodesInput) (*mcp.CallToolResult, ListPolicyNodesOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListPolicyNodesOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListPolicyNodesOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			group, name := getOrgString(in.PolicyGroup), getOrgString(in.PolicyName)
+			nodes, err := api.ListNodesByPolicy(group, name, org)
+			if err != nil {
+				return nil, ListPolicyNodesOutput{}, err
+			}
+			return nil, ListPolicyNodesOutput{Nodes: nodes, PolicyGroup: group, PolicyName: name, Organization: org}, nil
+		})
+
+	// listUserKeys
+	mcp.AddTool(server, &mcp.Tool{Name: "listUserKeys", Description: "List a Chef user's keys with expiration dates and public key metadata", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListUserKeysInput) (*mcp.CallToolResult, ListUserKeysOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListUserKeysOutput{}, err
+			}
+
+			keys, err := api.ListUserKeys(in.Name)
+			if err != nil {
+				return nil, ListUserKeysOutput{}, err
+			}
+			return nil, ListUserKeysOutput{User: in.Name, Keys: keys}, nil
+		})
+
+	// listClientKeys
+	mcp.AddTool(server, &mcp.Tool{Name: "listClientKeys", Description: "List an API client's keys with expiration dates and public key metadata - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListClientKeysInput) (*mcp.CallToolResult, ListClientKeysOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListClientKeysOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListClientKeysOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			keys, err := api.ListClientKeys(in.Name, org)
+			if err != nil {
+				return nil, ListClientKeysOutput{}, err
+			}
+			return nil, ListClientKeysOutput{Client: in.Name, Keys: keys, Organization: org}, nil
+		})
+
+	// keyExpirationReport
+	mcp.AddTool(server, &mcp.Tool{Name: "keyExpirationReport", Description: "Report user and client keys in an organization that are expired, expiring within N days, or never expire - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in KeyExpirationReportInput) (*mcp.CallToolResult, KeyExpirationReportOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, KeyExpirationReportOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, KeyExpirationReportOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			days := 30
+			if in.Days != nil && *in.Days >= 0 {
+				days = *in.Days
+			}
+			users, err := api.ListOrganizationMembers(org)
+			if err != nil {
+				return nil, KeyExpirationReportOutput{}, err
+			}
+			clients, err := api.ListClients(org)
+			if err != nil {
+				return nil, KeyExpirationReportOutput{}, err
+			}
+
+			now := time.Now().UTC()
+			window := time.Duration(days) * 24 * time.Hour
+			out := KeyExpirationReportOutput{Days: days, Keys: []KeyReportEntry{}, Summary: make(map[string]int), Organization: org}
+			add := func(actorType, actor string, keys []chefapi.KeyInfo) {
+				for _, k := range keys {
+					status, err := k.Status(now, window)
+					if err != nil {
+						out.Errors = append(out.Errors, fmt.Sprintf("%s %s: %v", actorType, actor, err))
+						continue
+					}
+					out.Summary[status]++
+					if status == chefapi.KeyValid && !in.IncludeValid {
+						continue
+					}
+					entry := KeyReportEntry{ActorType: actorType, Actor: actor, Key: k.Name, ExpirationDate: k.ExpirationDate, Status: status}
+					if t, infinite, _ := k.ExpiresAt(); !infinite {
+						remaining := int(t.Sub(now).Hours() / 24)
+						entry.DaysRemaining = &remaining
+					}
+					out.Keys = append(out.Keys, entry)
+				}
+			}
+
+			// Keys that cannot be read are reported as errors rather than failing the whole report
+			for _, u := range users {
+				keys, err := api.ListUserKeys(u)
+				if err != nil {
+					out.Errors = append(out.Errors, fmt.Sprintf("user %s: %v", u, err))
+					continue
+				}
+				add("user", u, keys)
+			}
+			for _, c := range clients {
+				keys, err := api.ListClientKeys(c, org)
+				if err != nil {
+					out.Errors = append(out.Errors, fmt.Sprintf("client %s: %v", c, err))
+					continue
+				}
+				add("client", c, keys)
+			}
+			return nil, out, nil
+		})
+
+	// listInvitations
+	mcp.AddTool(server, &mcp.Tool{Name: "listInvitations", Description: "List pending organization invitations (association requests) - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListInvitationsInput) (*mcp.CallToolResult, ListInvitationsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListInvitationsOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListInvitationsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			invites, err := api.ListInvitations(org)
+			if err != nil {
+				return nil, ListInvitationsOutput{}, err
+			}
+			return nil, ListInvitationsOutput{Invitations: invites, Organization: org}, nil
+		})
+
+	// listUserOrganizations
+	mcp.AddTool(server, &mcp.Tool{Name: "listUserOrganizations", Description: "List every organization a Chef user belongs to", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListUserOrganizationsInput) (*mcp.CallToolResult, ListUserOrganizationsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListUserOrganizationsOutput{}, err
+			}
+
+			orgs, err := api.ListUserOrganizations(in.Name)
+			if err != nil {
+				return nil, ListUserOrganizationsOutput{}, err
+			}
+			return nil, ListUserOrganizationsOutput{User: in.Name, Organizations: orgs}, nil
+		})
+
+	// membershipMatrix
+	mcp.AddTool(server, &mcp.Tool{Name: "membershipMatrix", Description: "Show which users belong to which organizations and whether they are admins, across all accessible organizations by default", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in MembershipMatrixInput) (*mcp.CallToolResult, MembershipMatrixOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, MembershipMatrixOutput{}, err
+			}
+
+			// Default to every accessible organization
+			if len(in.Organizations) == 0 {
+				in.Organizations = []string{"*"}
+			}
+			orgs, err := resolveOrgList(api, in.Organizations)
+			if err != nil {
+				return nil, MembershipMatrixOutput{}, err
+			}
+
+			out := MembershipMatrixOutput{Organizations: orgs, Users: make(map[string]map[string]Membership)}
+			fail := func(org string, err error) {
+				if out.Errors == nil {
+					out.Errors = make(map[string]string)
+				}
+				out.Errors[org] = err.Error()
+			}
+			for _, org := range orgs {
+				members, err := api.ListOrganizationMembers(org)
+				if err != nil {
+					fail(org, err)
+					continue
+				}
+				admins, err := api.ListOrganizationAdmins(org)
+				if err != nil {
+					fail(org, err)
+					continue
+				}
+				for _, u := range members {
+					if out.Users[u] == nil {
+						out.Users[u] = make(map[string]Membership)
+					}
+					out.Users[u][org] = Membership{Member: true, Admin: admins[u]}
+				}
+			}
+			return nil, out, nil
+		})
+
+	// search
+	mcp.AddTool(server, &mcp.Tool{Name: "search", Description: "Execute a Chef search and return decoded rows - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in SearchInput) (*mcp.CallToolResult, SearchOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, SearchOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, SearchOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			res, err := api.Search(in.Index, in.Query, org)
+			if err != nil {
+				return nil, SearchOutput{}, err
+			}
+			return nil, SearchOutput{Total: res.Total, Start: res.Start, Rows: res.Rows, Organization: org}, nil
+		})
+
+	// searchJSON
+	mcp.AddTool(server, &mcp.Tool{Name: "searchJSON", Description: "Execute a Chef search and return raw JSON rows - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in SearchJSONInput) (*mcp.CallToolResult, SearchJSONOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, SearchJSONOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, SearchJSONOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			res, err := api.SearchJSON(in.Index, in.Query, org)
+			if err != nil {
+				return nil, SearchJSONOutput{}, err
+			}
+			rows := make([]json.RawMessage, 0, len(res.Rows))
+			for _, r := range res.Rows { // marshal each row back to raw JSON blob
+				b, err := json.Marshal(r)
+				if err != nil {
+					return nil, SearchJSONOutput{}, err
+				}
+				rows = append(rows, b)
+			}
+			return nil, SearchJSONOutput{Total: res.Total, Start: res.Start, Rows: rows, Organization: org}, nil
+		})
+
+	// multiOrgSearch
+	mcp.AddTool(server, &mcp.Tool{Name: "multiOrgSearch", Description: "Execute a Chef search across several organizations concurrently; rows are tagged with their organization and per-org errors are reported without failing the call", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in MultiOrgSearchInput) (*mcp.CallToolResult, MultiOrgSearchOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, MultiOrgSearchOutput{}, err
+			}
+
+			orgs, err := resolveOrgList(api, in.Organizations)
+			if err != nil {
+				return nil, MultiOrgSearchOutput{}, err
+			}
+			if len(orgs) == 0 {
+				return nil, MultiOrgSearchOutput{}, fmt.Errorf("at least one organization must be specified")
+			}
+
+			parallel := 4
+			if in.MaxParallel != nil && *in.MaxParallel > 0 {
+				parallel = *in.MaxParallel
+			}
+
+			// Query each organization with bounded parallelism; results keep input order
+			results := make([]chef.SearchResult, len(orgs))
+			statuses := make([]OrgSearchStatus, len(orgs))
+			sem := make(chan struct{}, parallel)
+			var wg sync.WaitGroup
+			for i, org := range orgs {
+				wg.Add(1)
+				go func(i int, org string) {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+
+					statuses[i].Organization = org
+					if ctx.Err() != nil {
+						statuses[i].Error = ctx.Err().Error()
+						return
+					}
+					res, err := api.Search(in.Index, in.Query, org)
+					if err != nil {
+						statuses[i].Error = err.Error()
+						return
+					}
+					results[i] = res
+					statuses[i].Total = res.Total
+				}(i, org)
+			}
+			wg.Wait()
+
+			out := MultiOrgSearchOutput{Rows: []OrgSearchRow{}, Organizations: statuses}
+			for i, res := range results {
+				out.Total += res.Total
+				for _, row := range res.Rows {
+					out.Rows = append(out.Rows, OrgSearchRow{Organization: orgs[i], Row: row})
+				}
+			}
+			return nil, out, nil
+		})
+
+	// getOrganization
+	mcp.AddTool(server, &mcp.Tool{Name: "getOrganization", Description: "Get organization details - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetOrganizationInput) (*mcp.CallToolResult, GetOrganizationOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetOrganizationOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetOrganizationOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			orgDetails, err := api.GetOrganization(org)
+			if err != nil {
+				return nil, GetOrganizationOutput{}, err
+			}
+			return nil, GetOrganizationOutput{Organization: orgDetails, OrgName: org}, nil
+		})
+
+	// listOrganizations
+	mcp.AddTool(server, &mcp.Tool{Name: "listOrganizations", Description: "List organizations accessible to the configured Chef user with full names and configured aliases", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListOrganizationsInput) (*mcp.CallToolResult, ListOrganizationsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListOrganizationsOutput{}, err
+			}
+
+			orgs, err := api.ListOrganizations()
+			if err != nil {
+				return nil, ListOrganizationsOutput{}, err
+			}
+
+			// Invert the alias mapping so each organization lists its aliases
+			aliases := make(map[string][]string)
+			for alias, org := range cfg.OrgAliases {
+				aliases[org] = append(aliases[org], alias)
+			}
+
+			out := ListOrganizationsOutput{Organizations: make([]OrganizationEntry, 0, len(orgs))}
+			for _, o := range orgs {
+				a := aliases[o.Name]
+				sort.Strings(a)
+				out.Organizations = append(out.Organizations, OrganizationEntry{
+					Name:     o.Name,
+					FullName: o.FullName,
+					Guid:     o.Guid,
+					Aliases:  a,
+					Default:  o.Name == cfg.DefaultOrg,
+				})
+			}
+			return nil, out, nil
+		})
+
+	// serverStatus
+	mcp.AddTool(server, &mcp.Tool{Name: "serverStatus", Description: "Report Chef server health (/_status upstreams), license usage, supported API versions and round-trip latency", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ServerStatusInput) (*mcp.CallToolResult, ServerStatusOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ServerStatusOutput{}, err
+			}
+
+			// Each endpoint is reported independently so one failure does not hide the others
+			out := ServerStatusOutput{ServerURL: cfg.ChefServerURL}
+			status, latency, err := api.GetServerStatus()
+			out.LatencyMs = latency.Milliseconds()
+			if err != nil {
+				out.StatusError = err.Error()
+			} else {
+				out.Status = &status
+			}
+			license, licenseErr := api.GetLicense()
+			if licenseErr != nil {
+				out.LicenseError = licenseErr.Error()
+			} else {
+				out.License = &license
+			}
+			apiVersion, err := api.GetServerAPIVersion()
+			if err != nil {
+				out.APIVersionError = err.Error()
+			} else {
+				out.APIVersion = &apiVersion
+			}
+
+			// /license requires a valid signature, so it tells credential problems apart from server problems;
+			// any other failure leaves the credential state unknown
+			if licenseErr == nil || chefapi.IsAuthError(licenseErr) {
+				valid := licenseErr == nil
+				out.CredentialsValid = &valid
+			}
+			return nil, out, nil
+		})
+
+	// listCookbooks
+	mcp.AddTool(server, &mcp.Tool{Name: "listCookbooks", Description: "List Chef cookbooks and their versions - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListCookbooksInput) (*mcp.CallToolResult, ListCookbooksOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListCookbooksOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListCookbooksOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			cookbooks, err := api.ListCookbooks(org)
+			if err != nil {
+				return nil, ListCookbooksOutput{}, err
+			}
+			return nil, ListCookbooksOutput{Cookbooks: cookbooks, Organization: org}, nil
+		})
+
+	// getCookbook
+	mcp.AddTool(server, &mcp.Tool{Name: "getCookbook", Description: "Get a Chef cookbook by name and version (defaults to _latest) - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetCookbookInput) (*mcp.CallToolResult, GetCookbookOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetCookbookOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetCookbookOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			version := "_latest"
+			if in.Version != nil && *in.Version != "" {
+				version = *in.Version
+			}
+
+			cookbook, err := api.GetCookbook(in.Name, version, org)
+			if err != nil {
+				return nil, GetCookbookOutput{}, err
+			}
+			return nil, GetCookbookOutput{Cookbook: cookbook, Organization: org}, nil
+		})
+
+	// listCookbookArtifacts
+	mcp.AddTool(server, &mcp.Tool{Name: "listCookbookArtifacts", Description: "List Policyfile cookbook artifacts and their identifiers - optionally specify cookbook name and organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListCookbookArtifactsInput) (*mcp.CallToolResult, ListCookbookArtifactsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListCookbookArtifactsOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListCookbookArtifactsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			artifacts, err := api.ListCookbookArtifacts(getOrgString(in.Name), org)
+			if err != nil {
+				return nil, ListCookbookArtifactsOutput{}, err
+			}
+			return nil, ListCookbookArtifactsOutput{Artifacts: artifacts, Organization: org}, nil
+		})
+
+	// getCookbookArtifact
+	mcp.AddTool(server, &mcp.Tool{Name: "getCookbookArtifact", Description: "Get a Policyfile cookbook artifact by name and identifier (same manifest shape as getCookbook) - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetCookbookArtifactInput) (*mcp.CallToolResult, GetCookbookOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetCookbookOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetCookbookOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			cookbook, err := api.GetCookbookArtifact(in.Name, in.Identifier, org)
+			if err != nil {
+				return nil, GetCookbookOutput{}, err
+			}
+			return nil, GetCookbookOutput{Cookbook: cookbook, Identifier: in.Identifier, Organization: org}, nil
+		})
+
+	// getUniverse
+	mcp.AddTool(server, &mcp.Tool{Name: "getUniverse", Description: "Get every cookbook version with its dependencies in one call - optionally filter by cookbook names and specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetUniverseInput) (*mcp.CallToolResult, GetUniverseOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetUniverseOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetUniverseOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			universe, err := api.GetUniverse(org)
+			if err != nil {
+				return nil, GetUniverseOutput{}, err
+			}
+			wanted := make(map[string]bool, len(in.Names))
+			for _, n := range in.Names {
+				wanted[n] = true
+			}
+			cookbooks := make(map[string]map[string]map[string]string)
+			for name, book := range universe.Books {
+				if len(wanted) > 0 && !wanted[name] {
+					continue
+				}
+				versions := make(map[string]map[string]string, len(book.Versions))
+				for v, uv := range book.Versions {
+					versions[v] = uv.Dependencies
+				}
+				cookbooks[name] = versions
+			}
+			return nil, GetUniverseOutput{Cookbooks: cookbooks, Organization: org}, nil
+		})
+
+	// findCookbookDependents
+	mcp.AddTool(server, &mcp.Tool{Name: "findCookbookDependents", Description: "Find cookbook versions that depend on a cookbook, optionally at an exact constraint or allowing a given version - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in FindCookbookDependentsInput) (*mcp.CallToolResult, FindCookbookDependentsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, FindCookbookDependentsOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, FindCookbookDependentsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			var version *constraint.Version
+			if v := getOrgString(in.Version); v != "" {
+				parsed, err := constraint.ParseVersion(v)
+				if err != nil {
+					return nil, FindCookbookDependentsOutput{}, err
+				}
+				version = &parsed
+			}
+			wantConstraint := strings.TrimSpace(getOrgString(in.Constraint))
+
+			universe, err := api.GetUniverse(org)
+			if err != nil {
+				return nil, FindCookbookDependentsOutput{}, err
+			}
+			dependents := []CookbookDependent{}
+			for name, book := range universe.Books {
+				for v, uv := range book.Versions {
+					dep, ok := uv.Dependencies[in.Cookbook]
+					if !ok {
+						continue
+					}
+					if wantConstraint != "" && strings.Join(strings.Fields(dep), " ") != strings.Join(strings.Fields(wantConstraint), " ") {
+						continue
+					}
+					if version != nil {
+						c, err := constraint.Parse(dep)
+						if err != nil || !c.Check(*version) {
+							continue
+						}
+					}
+					dependents = append(dependents, CookbookDependent{Cookbook: name, Version: v, Constraint: dep})
+				}
+			}
+			sort.Slice(dependents, func(i, j int) bool {
+				if dependents[i].Cookbook != dependents[j].Cookbook {
+					return dependents[i].Cookbook < dependents[j].Cookbook
+				}
+				return constraint.CompareStrings(dependents[i].Version, dependents[j].Version) < 0
+			})
+			return nil, FindCookbookDependentsOutput{Cookbook: in.Cookbook, Dependents: dependents, Organization: org}, nil
+		})
+
+	// listDataBags
+	mcp.AddTool(server, &mcp.Tool{Name: "listDataBags", Description: "List Chef data bags - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListDataBagsInput) (*mcp.CallToolResult, ListDataBagsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListDataBagsOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListDataBagsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			dataBags, err := api.ListDataBags(org)
+			if err != nil {
+				return nil, ListDataBagsOutput{}, err
+			}
+			return nil, ListDataBagsOutput{DataBags: dataBags, Organization: org}, nil
+		})
+
+	// listDataBagItems
+	mcp.AddTool(server, &mcp.Tool{Name: "listDataBagItems", Description: "List items in a Chef data bag - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListDataBagItemsInput) (*mcp.CallToolResult, ListDataBagItemsOutput, error) {
 			api, err := needAPI()
 			if err != nil {
 				return nil, ListDataBagItemsOutput{}, err
@@ -509,67 +1934,1215 @@ func main() {
 		})
 
 	// getDataBagItem
-	mcp.AddTool(server, &mcp.Tool{Name: "getDataBagItem", Description: "Get a specific item from a Chef data bag - optionally specify organization"},
+	mcp.AddTool(server, &mcp.Tool{Name: "getDataBagItem", Description: "Get a specific item from a Chef data bag - optionally specify organization", Annotations: readOnlyTool},
 		func(ctx context.Context, req *mcp.CallToolRequest, in GetDataBagItemInput) (*mcp.CallToolResult, GetDataBagItemOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, GetDataBagItemOutput{}, err
+				return nil, GetDataBagItemOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetDataBagItemOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			item, err := api.GetDataBagItem(in.BagName, in.ItemName, org)
+			if err != nil {
+				return nil, GetDataBagItemOutput{}, err
+			}
+			out := GetDataBagItemOutput{Item: item, DataBag: in.BagName, Organization: org}
+			fields, _ := (*item).(map[string]interface{})
+			out.Encrypted = fields != nil && databag.IsEncrypted(fields)
+			if !in.Decrypt || !out.Encrypted {
+				return nil, out, nil
+			}
+
+			// Decryption is opt-in per bag; plaintext needs both the caller and the server to allow it
+			if !cfg.DecryptAllowed(in.BagName) {
+				return nil, GetDataBagItemOutput{}, fmt.Errorf("decryption of data bag '%s' is not allowed (see CHEF_DECRYPT_DATA_BAGS)", in.BagName)
+			}
+			if in.Plaintext && !cfg.AllowPlaintext {
+				return nil, GetDataBagItemOutput{}, fmt.Errorf("plaintext output is disabled (set CHEF_ALLOW_PLAINTEXT_SECRETS=true to permit it)")
+			}
+			secretPath := cfg.DataBagSecretPath(in.BagName)
+			if secretPath == "" {
+				return nil, GetDataBagItemOutput{}, fmt.Errorf("no secret configured for data bag '%s' (see CHEF_DATA_BAG_SECRET_FILE, CHEF_DATA_BAG_SECRETS)", in.BagName)
+			}
+			secret, err := databag.LoadSecret(secretPath)
+			if err != nil {
+				return nil, GetDataBagItemOutput{}, err
+			}
+			decrypted, err := databag.Decrypt(fields, secret)
+			if err != nil {
+				return nil, GetDataBagItemOutput{}, err
+			}
+			if !in.Plaintext {
+				decrypted = databag.Redact(decrypted)
+				out.Redacted = true
+			}
+			var result chef.DataBagItem = decrypted
+			out.Item = &result
+			out.Decrypted = true
+			if in.Plaintext {
+				return plaintextResult(), out, nil
+			}
+			return nil, out, nil
+		})
+
+	// getVaultInfo
+	mcp.AddTool(server, &mcp.Tool{Name: "getVaultInfo", Description: "Read chef-vault metadata: admins and clients able to decrypt an item and the search query used - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetVaultInfoInput) (*mcp.CallToolResult, GetVaultInfoOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetVaultInfoOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetVaultInfoOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			keys, err := getVaultKeys(api, in.Vault, in.Item, org)
+			if err != nil {
+				return nil, GetVaultInfoOutput{}, err
+			}
+			meta := databag.ParseVaultKeys(keys)
+			canDecrypt := false
+			for _, admin := range meta.Admins {
+				canDecrypt = canDecrypt || admin == cfg.ChefUser
+			}
+			return nil, GetVaultInfoOutput{Vault: in.Vault, Item: in.Item, Metadata: meta, CanDecrypt: canDecrypt, Organization: org}, nil
+		})
+
+	// getVaultItem
+	mcp.AddTool(server, &mcp.Tool{Name: "getVaultItem", Description: "Decrypt a chef-vault item using the configured CHEF_USER key (must be a vault admin; vault must be allowed by CHEF_DECRYPT_DATA_BAGS) - values are redacted unless plaintext is requested and permitted", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetVaultItemInput) (*mcp.CallToolResult, GetVaultItemOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetVaultItemOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetVaultItemOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			// Same opt-in controls as encrypted data bags
+			if !cfg.DecryptAllowed(in.Vault) {
+				return nil, GetVaultItemOutput{}, fmt.Errorf("decryption of vault '%s' is not allowed (see CHEF_DECRYPT_DATA_BAGS)", in.Vault)
+			}
+			if in.Plaintext && !cfg.AllowPlaintext {
+				return nil, GetVaultItemOutput{}, fmt.Errorf("plaintext output is disabled (set CHEF_ALLOW_PLAINTEXT_SECRETS=true to permit it)")
+			}
+
+			keys, err := getVaultKeys(api, in.Vault, in.Item, org)
+			if err != nil {
+				return nil, GetVaultItemOutput{}, err
+			}
+			encSecret, err := databag.VaultSharedSecret(keys, in.Item, cfg.ChefUser, func(name string) (map[string]interface{}, error) {
+				sparse, err := api.GetDataBagItem(in.Vault, name, org)
+				if chefapi.IsNotFound(err) {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				fields, _ := (*sparse).(map[string]interface{})
+				return fields, nil
+			})
+			if errors.Is(err, databag.ErrNoVaultKey) {
+				return nil, GetVaultItemOutput{}, fmt.Errorf("user '%s' is not an admin of vault item '%s/%s'", cfg.ChefUser, in.Vault, in.Item)
+			}
+			if err != nil {
+				return nil, GetVaultItemOutput{}, err
+			}
+
+			item, err := api.GetDataBagItem(in.Vault, in.Item, org)
+			if err != nil {
+				return nil, GetVaultItemOutput{}, err
+			}
+			fields, _ := (*item).(map[string]interface{})
+			decrypted, err := databag.DecryptVault(fields, encSecret, api.DecryptWithKey)
+			if err != nil {
+				return nil, GetVaultItemOutput{}, err
+			}
+			out := GetVaultItemOutput{Item: decrypted, Vault: in.Vault, Organization: org}
+			if !in.Plaintext {
+				out.Item = databag.Redact(decrypted)
+				out.Redacted = true
+				return nil, out, nil
+			}
+			return plaintextResult(), out, nil
+		})
+
+	// listEnvironments
+	mcp.AddTool(server, &mcp.Tool{Name: "listEnvironments", Description: "List Chef environments - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListEnvironmentsInput) (*mcp.CallToolResult, ListEnvironmentsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListEnvironmentsOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListEnvironmentsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			environments, err := api.ListEnvironments(org)
+			if err != nil {
+				return nil, ListEnvironmentsOutput{}, err
+			}
+			return nil, ListEnvironmentsOutput{Environments: environments, Organization: org}, nil
+		})
+
+	// getEnvironment
+	mcp.AddTool(server, &mcp.Tool{Name: "getEnvironment", Description: "Get a Chef environment by name - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in GetEnvironmentInput) (*mcp.CallToolResult, GetEnvironmentOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, GetEnvironmentOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, GetEnvironmentOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			environment, err := api.GetEnvironment(in.Name, org)
+			if err != nil {
+				return nil, GetEnvironmentOutput{}, err
+			}
+			return nil, GetEnvironmentOutput{Environment: environment, Organization: org}, nil
+		})
+
+	// diffNodes
+	mcp.AddTool(server, &mcp.Tool{Name: "diffNodes", Description: "Compare two Chef nodes (run list, environment and each attribute precedence level) - nodes may live in different organizations", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in DiffNodesInput) (*mcp.CallToolResult, DiffNodesOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, DiffNodesOutput{}, err
+			}
+
+			// Resolve both organizations independently
+			orgA := cfg.ResolveOrganization(getOrgString(in.OrganizationA))
+			orgB := cfg.ResolveOrganization(getOrgString(in.OrganizationB))
+			if orgA == "" || orgB == "" {
+				return nil, DiffNodesOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			a, err := api.GetNode(in.NodeA, orgA)
+			if err != nil {
+				return nil, DiffNodesOutput{}, fmt.Errorf("get node '%s' in org '%s': %w", in.NodeA, orgA, err)
+			}
+			b, err := api.GetNode(in.NodeB, orgB)
+			if err != nil {
+				return nil, DiffNodesOutput{}, fmt.Errorf("get node '%s' in org '%s': %w", in.NodeB, orgB, err)
+			}
+
+			ignore := cfg.DiffIgnore
+			if len(in.IgnorePaths) > 0 {
+				ignore = in.IgnorePaths
+			}
+
+			out := DiffNodesOutput{
+				NodeA:         in.NodeA,
+				NodeB:         in.NodeB,
+				OrganizationA: orgA,
+				OrganizationB: orgB,
+				Attributes:    make(map[string][]diff.Change),
+				IgnoredPaths:  ignore,
+			}
+			if a.Environment != b.Environment {
+				out.Environment = &diff.Change{Path: "chef_environment", Kind: diff.Changed, Old: a.Environment, New: b.Environment}
+			}
+			out.RunList = diffRunLists(a.RunList, b.RunList)
+			levels := []struct {
+				name string
+				a, b map[string]interface{}
+			}{
+				{"default", a.DefaultAttributes, b.DefaultAttributes},
+				{"normal", a.NormalAttributes, b.NormalAttributes},
+				{"override", a.OverrideAttributes, b.OverrideAttributes},
+				{"automatic", a.AutomaticAttributes, b.AutomaticAttributes},
+			}
+			for _, l := range levels {
+				out.Attributes[l.name] = diff.Compare(l.a, l.b, ignore)
+			}
+			return nil, out, nil
+		})
+
+	// diffAcrossOrgs
+	mcp.AddTool(server, &mcp.Tool{Name: "diffAcrossOrgs", Description: "Compare an environment, role or data bag item with the same name in two organizations (aliases accepted)", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in DiffAcrossOrgsInput) (*mcp.CallToolResult, DiffAcrossOrgsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, DiffAcrossOrgsOutput{}, err
+			}
+
+			// Both sides are explicit; the default organization would make the comparison meaningless
+			if in.OrganizationA == "" || in.OrganizationB == "" {
+				return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("organizationA and organizationB must both be specified")
+			}
+			orgA := cfg.ResolveOrganization(in.OrganizationA)
+			orgB := cfg.ResolveOrganization(in.OrganizationB)
+
+			out := DiffAcrossOrgsOutput{Kind: in.Kind, Name: in.Name, OrganizationA: orgA, OrganizationB: orgB}
+			switch in.Kind {
+			case "environment":
+				a, err := api.GetEnvironment(in.Name, orgA)
+				if err != nil {
+					return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("get environment '%s' in org '%s': %w", in.Name, orgA, err)
+				}
+				b, err := api.GetEnvironment(in.Name, orgB)
+				if err != nil {
+					return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("get environment '%s' in org '%s': %w", in.Name, orgB, err)
+				}
+				out.CookbookVersions = diff.Compare(a.CookbookVersions, b.CookbookVersions, nil)
+				out.Attributes = map[string][]diff.Change{
+					"default":  diff.Compare(a.DefaultAttributes, b.DefaultAttributes, nil),
+					"override": diff.Compare(a.OverrideAttributes, b.OverrideAttributes, nil),
+				}
+				if a.Description != b.Description {
+					out.Other = append(out.Other, diff.Change{Path: "description", Kind: diff.Changed, Old: a.Description, New: b.Description})
+				}
+			case "role":
+				a, err := api.GetRole(in.Name, orgA)
+				if err != nil {
+					return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("get role '%s' in org '%s': %w", in.Name, orgA, err)
+				}
+				b, err := api.GetRole(in.Name, orgB)
+				if err != nil {
+					return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("get role '%s' in org '%s': %w", in.Name, orgB, err)
+				}
+				rl := diffRunLists(a.RunList, b.RunList)
+				out.RunList = &rl
+				out.EnvRunLists = make(map[string]RunListDiff)
+				for env := range a.EnvRunList {
+					out.EnvRunLists[env] = diffRunLists(a.EnvRunList[env], b.EnvRunList[env])
+				}
+				for env := range b.EnvRunList {
+					if _, seen := out.EnvRunLists[env]; !seen {
+						out.EnvRunLists[env] = diffRunLists(nil, b.EnvRunList[env])
+					}
+				}
+				out.Attributes = map[string][]diff.Change{
+					"default":  diff.Compare(a.DefaultAttributes, b.DefaultAttributes, nil),
+					"override": diff.Compare(a.OverrideAttributes, b.OverrideAttributes, nil),
+				}
+				if a.Description != b.Description {
+					out.Other = append(out.Other, diff.Change{Path: "description", Kind: diff.Changed, Old: a.Description, New: b.Description})
+				}
+			case "dataBagItem":
+				bag := getOrgString(in.BagName)
+				if bag == "" {
+					return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("bagName is required for kind dataBagItem")
+				}
+				a, err := api.GetDataBagItem(bag, in.Name, orgA)
+				if err != nil {
+					return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("get data bag item '%s/%s' in org '%s': %w", bag, in.Name, orgA, err)
+				}
+				b, err := api.GetDataBagItem(bag, in.Name, orgB)
+				if err != nil {
+					return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("get data bag item '%s/%s' in org '%s': %w", bag, in.Name, orgB, err)
+				}
+				out.Other = diff.Compare(*a, *b, nil)
+			default:
+				return nil, DiffAcrossOrgsOutput{}, fmt.Errorf("unsupported kind '%s' (expected environment, role or dataBagItem)", in.Kind)
+			}
+
+			out.Identical = len(out.CookbookVersions) == 0 && len(out.Other) == 0 && (out.RunList == nil || out.RunList.empty())
+			for _, changes := range out.Attributes {
+				out.Identical = out.Identical && len(changes) == 0
+			}
+			for _, rl := range out.EnvRunLists {
+				out.Identical = out.Identical && rl.empty()
+			}
+			return nil, out, nil
+		})
+
+	// listTags
+	mcp.AddTool(server, &mcp.Tool{Name: "listTags", Description: "Count node tags across the fleet (or nodes matching a search query), most used first - optionally specify organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListTagsInput) (*mcp.CallToolResult, ListTagsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListTagsOutput{}, err
+			}
+
+			// Resolve organization
+			org := cfg.ResolveOrganization(getOrgString(in.Organization))
+			if org == "" {
+				return nil, ListTagsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+
+			query := getOrgString(in.Query)
+			if query == "" {
+				query = "*:*"
+			}
+			tags, nodes, err := api.CountTags(query, org)
+			if err != nil {
+				return nil, ListTagsOutput{}, err
+			}
+			return nil, ListTagsOutput{Organization: org, Query: query, NodeCount: nodes, Tags: tags}, nil
+		})
+
+	// listChangeJournal
+	mcp.AddTool(server, &mcp.Tool{Name: "listChangeJournal", Description: "List snapshots taken before changes made through this server (tool, caller, time, object), newest first - optionally filter by kind, name and organization", Annotations: readOnlyTool},
+		func(ctx context.Context, req *mcp.CallToolRequest, in ListChangeJournalInput) (*mcp.CallToolResult, ListChangeJournalOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return nil, ListChangeJournalOutput{}, err
+			}
+			if api.Journal == nil {
+				return nil, ListChangeJournalOutput{}, fmt.Errorf("change journal is not configured (see CHEF_JOURNAL_DIR)")
+			}
+
+			limit := in.Limit
+			if limit <= 0 {
+				limit = 50
+			}
+			entries, err := api.Journal.List(journal.Filter{
+				Organization: cfg.ResolveOrganization(getOrgString(in.Organization)),
+				Kind:         getOrgString(in.Kind),
+				Name:         getOrgString(in.Name),
+				Limit:        limit,
+			})
+			if err != nil {
+				return nil, ListChangeJournalOutput{}, err
+			}
+			return nil, ListChangeJournalOutput{Directory: api.Journal.Dir(), Entries: entries}, nil
+		})
+
+	// Write tools are only registered in write mode; read-only sessions never see them
+	if cfg.WriteEnabled() {
+		log.Printf("write mode enabled: registering mutating tools")
+
+		// Every write tool is two-phase. Called without a confirmation it only computes its plan and
+		// returns a single-use confirmation bound to the plan's hash; called again with that confirmation
+		// it recomputes the plan and applies it only if nothing changed in between.
+		plans := confirm.NewStore(confirm.DefaultTTL)
+		planGate := func(tool string, confirmation *string, plan interface{}) (PlanStatus, bool, error) {
+			hash, err := confirm.Hash(tool, plan)
+			if err != nil {
+				return PlanStatus{}, false, err
+			}
+			if confirmation == nil || *confirmation == "" {
+				id, expires, err := plans.Issue(hash)
+				if err != nil {
+					return PlanStatus{}, false, err
+				}
+				return PlanStatus{Confirmation: id, ExpiresAt: &expires, PlanHash: hash}, false, nil
+			}
+			if err := plans.Redeem(*confirmation, hash); err != nil {
+				return PlanStatus{}, false, err
+			}
+			return PlanStatus{PlanHash: hash}, true, nil
+		}
+
+		// updateNodeRunList
+		mcp.AddTool(server, &mcp.Tool{Name: "updateNodeRunList", Description: "Add, remove or replace node run-list entries after validating referenced roles and recipes - dry run by default, returning a plan (run list and expanded recipes before and after) with a confirmation; call again with dryRun=false and the confirmation to apply", Annotations: destructiveTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in UpdateNodeRunListInput) (*mcp.CallToolResult, UpdateNodeRunListOutput, error) {
+				api, err := needAPI()
+				if err != nil {
+					return nil, UpdateNodeRunListOutput{}, err
+				}
+
+				// Resolve organization
+				org := cfg.ResolveOrganization(getOrgString(in.Organization))
+				if org == "" {
+					return nil, UpdateNodeRunListOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				}
+
+				// Nothing is applied unless dry-run is explicitly disabled
+				dryRun := in.DryRun == nil || *in.DryRun
+				if dryRun && in.Confirmation != nil && *in.Confirmation != "" {
+					return nil, UpdateNodeRunListOutput{}, fmt.Errorf("confirmation given but dryRun is not false: set dryRun=false to apply the plan")
+				}
+
+				items, err := chefapi.NormalizeRunList(in.Items)
+				if err != nil {
+					return nil, UpdateNodeRunListOutput{}, err
+				}
+				node, err := api.GetNode(in.NodeName, org)
+				if err != nil {
+					return nil, UpdateNodeRunListOutput{}, err
+				}
+				before, err := chefapi.NormalizeRunList(node.RunList)
+				if err != nil {
+					return nil, UpdateNodeRunListOutput{}, fmt.Errorf("node '%s' has an invalid run list: %w", in.NodeName, err)
+				}
+				after, err := editRunList(before, in.Action, items, in.Position, in.Target)
+				if err != nil {
+					return nil, UpdateNodeRunListOutput{}, err
+				}
+
+				out := UpdateNodeRunListOutput{
+					NodeName:     in.NodeName,
+					Organization: org,
+					Environment:  node.Environment,
+					DryRun:       dryRun,
+					Before:       before,
+					After:        after,
+					RunListDiff:  diffRunLists(before, after),
+				}
+
+				// Only entries being introduced need to exist; removals of stale entries are always allowed
+				added, _ := diff.StringSet(before, after)
+				out.Problems, err = api.ValidateRunList(added, org)
+				if err != nil {
+					return nil, UpdateNodeRunListOutput{}, err
+				}
+				if len(out.Problems) > 0 {
+					return nil, out, nil
+				}
+
+				if out.RecipesBefore, err = api.ExpandRunList(before, node.Environment, org); err != nil {
+					return nil, UpdateNodeRunListOutput{}, err
+				}
+				if out.RecipesAfter, err = api.ExpandRunList(after, node.Environment, org); err != nil {
+					return nil, UpdateNodeRunListOutput{}, err
+				}
+				recipeDiff := diffRunLists(out.RecipesBefore, out.RecipesAfter)
+				out.RecipeDiff = &recipeDiff
+
+				if out.RunListDiff.empty() {
+					return nil, out, nil
+				}
+				// The dry-run plan and the apply call must bind to the same hash
+				plan := out
+				plan.DryRun = false
+				status, apply, err := planGate("updateNodeRunList", in.Confirmation, plan)
+				out.PlanStatus = status
+				if err != nil || !apply {
+					return nil, out, err
+				}
+				_, _, err = api.ModifyNode(changeFor(req, "updateNodeRunList"), in.NodeName, org, func(node *chef.Node) (bool, error) {
+					current, err := chefapi.NormalizeRunList(node.RunList)
+					if err != nil || strings.Join(current, ",") != strings.Join(before, ",") {
+						return false, confirm.ErrChanged
+					}
+					node.RunList = after
+					return true, nil
+				})
+				if err != nil {
+					return nil, UpdateNodeRunListOutput{}, err
+				}
+				out.Applied = true
+				return nil, out, nil
+			})
+
+		// addNodeTags and removeNodeTags share everything but the edit applied to the tag list
+		updateTags := func(change chefapi.Change, in NodeTagsInput, edit func(current []string) []string) (NodeTagsOutput, error) {
+			api, err := needAPI()
+			if err != nil {
+				return NodeTagsOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, GetDataBagItemOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				return NodeTagsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+			if len(in.Tags) == 0 {
+				return NodeTagsOutput{}, fmt.Errorf("tags must not be empty")
 			}
 
-			item, err := api.GetDataBagItem(in.BagName, in.ItemName, org)
+			node, err := api.GetNode(in.NodeName, org)
 			if err != nil {
-				return nil, GetDataBagItemOutput{}, err
+				return NodeTagsOutput{}, err
+			}
+			before := chefapi.NodeTags(node)
+			after := edit(before)
+			out := NodeTagsOutput{
+				NodeName:     in.NodeName,
+				Organization: org,
+				Before:       before,
+				After:        after,
+				Changed:      strings.Join(before, ",") != strings.Join(after, ","),
+			}
+			if !out.Changed {
+				return out, nil
+			}
+			status, apply, err := planGate(change.Tool, in.Confirmation, out)
+			out.PlanStatus = status
+			if err != nil || !apply {
+				return out, err
 			}
-			return nil, GetDataBagItemOutput{Item: item, DataBag: in.BagName, Organization: org}, nil
-		})
 
-	// listEnvironments
-	mcp.AddTool(server, &mcp.Tool{Name: "listEnvironments", Description: "List Chef environments - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in ListEnvironmentsInput) (*mcp.CallToolResult, ListEnvironmentsOutput, error) {
+			// Other attributes may change under us (chef-client runs); only the tags must match the plan
+			_, _, err = api.ModifyNode(change, in.NodeName, org, func(node *chef.Node) (bool, error) {
+				if strings.Join(chefapi.NodeTags(node), ",") != strings.Join(before, ",") {
+					return false, confirm.ErrChanged
+				}
+				chefapi.SetNodeTags(node, after)
+				return true, nil
+			})
+			if err != nil {
+				return NodeTagsOutput{}, err
+			}
+			out.Applied = true
+			return out, nil
+		}
+
+		// addNodeTags
+		mcp.AddTool(server, &mcp.Tool{Name: "addNodeTags", Description: "Add tags to a node's normal.tags; the node is saved whole, so a chef-client run saving it at the same moment can overwrite the change - returns a plan with a confirmation; call again with the confirmation to apply", Annotations: destructiveTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in NodeTagsInput) (*mcp.CallToolResult, NodeTagsOutput, error) {
+				out, err := updateTags(changeFor(req, "addNodeTags"), in, func(current []string) []string {
+					updated := append([]string{}, current...)
+					for _, tag := range in.Tags {
+						if !slices.Contains(updated, tag) {
+							updated = append(updated, tag)
+						}
+					}
+					return updated
+				})
+				return nil, out, err
+			})
+
+		// removeNodeTags
+		mcp.AddTool(server, &mcp.Tool{Name: "removeNodeTags", Description: "Remove tags from a node's normal.tags; the node is saved whole, so a chef-client run saving it at the same moment can overwrite the change - returns a plan with a confirmation; call again with the confirmation to apply", Annotations: destructiveTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in NodeTagsInput) (*mcp.CallToolResult, NodeTagsOutput, error) {
+				out, err := updateTags(changeFor(req, "removeNodeTags"), in, func(current []string) []string {
+					kept, _ := diff.StringSet(in.Tags, current)
+					return kept
+				})
+				return nil, out, err
+			})
+
+		// createDataBag
+		mcp.AddTool(server, &mcp.Tool{Name: "createDataBag", Description: "Create an empty data bag - returns a plan with a confirmation; call again with the confirmation to apply - optionally specify organization", Annotations: writeTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in CreateDataBagInput) (*mcp.CallToolResult, CreateDataBagOutput, error) {
+				api, err := needAPI()
+				if err != nil {
+					return nil, CreateDataBagOutput{}, err
+				}
+
+				// Resolve organization
+				org := cfg.ResolveOrganization(getOrgString(in.Organization))
+				if org == "" {
+					return nil, CreateDataBagOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				}
+				if !chefNamePattern.MatchString(in.BagName) {
+					return nil, CreateDataBagOutput{}, fmt.Errorf("invalid data bag name '%s'", in.BagName)
+				}
+
+				bags, err := api.ListDataBags(org)
+				if err != nil {
+					return nil, CreateDataBagOutput{}, err
+				}
+				if slices.Contains(bags, in.BagName) {
+					return nil, CreateDataBagOutput{}, fmt.Errorf("data bag '%s' already exists", in.BagName)
+				}
+
+				out := CreateDataBagOutput{BagName: in.BagName, Organization: org}
+				status, apply, err := planGate("createDataBag", in.Confirmation, out)
+				out.PlanStatus = status
+				if err != nil || !apply {
+					return nil, out, err
+				}
+				if err := api.CreateDataBag(changeFor(req, "createDataBag"), in.BagName, org); err != nil {
+					return nil, CreateDataBagOutput{}, err
+				}
+				out.Applied = true
+				return nil, out, nil
+			})
+
+		// createDataBagItem and updateDataBagItem validate and diff the same way; only the existence check
+		// and the final API call differ
+		writeDataBagItem := func(req *mcp.CallToolRequest, in DataBagItemWriteInput, create bool) (DataBagItemWriteOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, ListEnvironmentsOutput{}, err
+				return DataBagItemWriteOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, ListEnvironmentsOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				return DataBagItemWriteOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+			if !chefNamePattern.MatchString(in.ItemName) {
+				return DataBagItemWriteOutput{}, fmt.Errorf("invalid data bag item name '%s'", in.ItemName)
+			}
+			if in.Item == nil {
+				return DataBagItemWriteOutput{}, fmt.Errorf("item must not be empty")
+			}
+			// Items read through this server are redacted; writing one back would destroy the real secrets
+			if path, found := redact.FindMarker(in.Item); found {
+				return DataBagItemWriteOutput{}, fmt.Errorf("item field '%s' holds a redaction placeholder: refusing to overwrite a secret with it, supply the real value", path)
 			}
 
-			environments, err := api.ListEnvironments(org)
+			// The item id is the item name; fill it in when omitted, refuse a mismatch
+			item := make(map[string]interface{}, len(in.Item)+1)
+			for k, v := range in.Item {
+				item[k] = v
+			}
+			if id, ok := item["id"]; !ok {
+				item["id"] = in.ItemName
+			} else if id != in.ItemName {
+				return DataBagItemWriteOutput{}, fmt.Errorf("item id '%v' does not match item name '%s'", id, in.ItemName)
+			}
+
+			var existing map[string]interface{}
+			current, err := api.GetDataBagItem(in.BagName, in.ItemName, org)
+			switch {
+			case err == nil:
+				if create {
+					return DataBagItemWriteOutput{}, fmt.Errorf("data bag item '%s/%s' already exists", in.BagName, in.ItemName)
+				}
+				existing, _ = (*current).(map[string]interface{})
+			case chefapi.IsNotFound(err):
+				if !create {
+					return DataBagItemWriteOutput{}, fmt.Errorf("data bag item '%s/%s' does not exist", in.BagName, in.ItemName)
+				}
+			default:
+				return DataBagItemWriteOutput{}, err
+			}
+
+			out := DataBagItemWriteOutput{
+				BagName:      in.BagName,
+				ItemName:     in.ItemName,
+				Organization: org,
+				Encrypted:    databag.IsEncrypted(item),
+			}
+
+			// Never let plaintext land in a bag holding encrypted items
+			if !out.Encrypted {
+				encrypted := existing != nil && databag.IsEncrypted(existing)
+				if !encrypted {
+					if encrypted, err = dataBagEncrypted(api, cfg, in.BagName, org); err != nil {
+						return DataBagItemWriteOutput{}, err
+					}
+				}
+				if encrypted {
+					return DataBagItemWriteOutput{}, fmt.Errorf("refusing to write plaintext into encrypted data bag '%s': supply an encrypted item", in.BagName)
+				}
+			}
+
+			// Schema items must compile; other plaintext items are validated against the bag's schema
+			if in.ItemName == databag.SchemaItem {
+				if _, err := databag.SchemaFromItem(item); err != nil {
+					return DataBagItemWriteOutput{}, err
+				}
+			} else if !out.Encrypted {
+				schema, source, err := dataBagSchema(api, cfg, in.BagName, org)
+				if err != nil {
+					return DataBagItemWriteOutput{}, err
+				}
+				if schema != nil {
+					if err := databag.Validate(schema, item); err != nil {
+						return DataBagItemWriteOutput{}, fmt.Errorf("item does not match schema from %s: %w", source, err)
+					}
+					out.Schema = source
+				}
+			}
+
+			var before interface{}
+			if existing != nil {
+				before = existing
+			}
+			out.Changes = diff.Compare(before, item, nil)
+			if len(out.Changes) == 0 {
+				return out, nil
+			}
+			tool := "updateDataBagItem"
+			if create {
+				tool = "createDataBagItem"
+			}
+			status, apply, err := planGate(tool, in.Confirmation, out)
+			out.PlanStatus = status
+			if err != nil || !apply {
+				return out, err
+			}
+			if create {
+				err = api.CreateDataBagItem(changeFor(req, tool), in.BagName, item, org)
+			} else {
+				err = api.UpdateDataBagItem(changeFor(req, tool), in.BagName, in.ItemName, item, org)
+			}
 			if err != nil {
-				return nil, ListEnvironmentsOutput{}, err
+				return DataBagItemWriteOutput{}, err
 			}
-			return nil, ListEnvironmentsOutput{Environments: environments, Organization: org}, nil
-		})
+			out.Applied = true
+			return out, nil
+		}
 
-	// getEnvironment
-	mcp.AddTool(server, &mcp.Tool{Name: "getEnvironment", Description: "Get a Chef environment by name - optionally specify organization"},
-		func(ctx context.Context, req *mcp.CallToolRequest, in GetEnvironmentInput) (*mcp.CallToolResult, GetEnvironmentOutput, error) {
+		// createDataBagItem
+		mcp.AddTool(server, &mcp.Tool{Name: "createDataBagItem", Description: "Create a data bag item, validating its id and the bag's JSON Schema and refusing plaintext in encrypted bags - returns a plan with the diff and a confirmation; call again with the confirmation to apply", Annotations: writeTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in DataBagItemWriteInput) (*mcp.CallToolResult, DataBagItemWriteOutput, error) {
+				out, err := writeDataBagItem(req, in, true)
+				return nil, out, err
+			})
+
+		// updateDataBagItem
+		mcp.AddTool(server, &mcp.Tool{Name: "updateDataBagItem", Description: "Replace an existing data bag item, validating its id and the bag's JSON Schema and refusing plaintext in encrypted bags - returns a plan with the diff and a confirmation; call again with the confirmation to apply", Annotations: destructiveTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in DataBagItemWriteInput) (*mcp.CallToolResult, DataBagItemWriteOutput, error) {
+				out, err := writeDataBagItem(req, in, false)
+				return nil, out, err
+			})
+
+		// setEnvironmentCookbookPin
+		mcp.AddTool(server, &mcp.Tool{Name: "setEnvironmentCookbookPin", Description: "Set an environment's cookbook version constraint after checking matching versions exist and representative run lists still depsolve - returns a plan with a confirmation; call again with the confirmation to apply", Annotations: destructiveTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in SetEnvironmentCookbookPinInput) (*mcp.CallToolResult, SetEnvironmentCookbookPinOutput, error) {
+				api, err := needAPI()
+				if err != nil {
+					return nil, SetEnvironmentCookbookPinOutput{}, err
+				}
+
+				// Resolve organization
+				org := cfg.ResolveOrganization(getOrgString(in.Organization))
+				if org == "" {
+					return nil, SetEnvironmentCookbookPinOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				}
+				if in.Environment == "_default" {
+					return nil, SetEnvironmentCookbookPinOutput{}, fmt.Errorf("the _default environment cannot be modified")
+				}
+				if strings.TrimSpace(in.Constraint) == "" {
+					return nil, SetEnvironmentCookbookPinOutput{}, fmt.Errorf("constraint must not be empty")
+				}
+				c, err := constraint.Parse(in.Constraint)
+				if err != nil {
+					return nil, SetEnvironmentCookbookPinOutput{}, err
+				}
+
+				// At least one uploaded version must satisfy the new pin
+				versions, err := api.ListCookbookVersions(in.Cookbook, org)
+				if err != nil {
+					if chefapi.IsNotFound(err) {
+						return nil, SetEnvironmentCookbookPinOutput{}, fmt.Errorf("cookbook '%s' does not exist", in.Cookbook)
+					}
+					return nil, SetEnvironmentCookbookPinOutput{}, err
+				}
+				out := SetEnvironmentCookbookPinOutput{
+					Environment:      in.Environment,
+					Organization:     org,
+					Cookbook:         in.Cookbook,
+					Constraint:       c.String(),
+					MatchingVersions: []string{},
+				}
+				for _, v := range versions {
+					if ok, err := constraint.Satisfies(v, out.Constraint); err == nil && ok {
+						out.MatchingVersions = append(out.MatchingVersions, v)
+					}
+				}
+				if len(out.MatchingVersions) == 0 {
+					return nil, SetEnvironmentCookbookPinOutput{}, fmt.Errorf("no version of cookbook '%s' satisfies '%s' (available: %s)", in.Cookbook, out.Constraint, strings.Join(versions, ", "))
+				}
+
+				env, err := api.GetEnvironment(in.Environment, org)
+				if err != nil {
+					return nil, SetEnvironmentCookbookPinOutput{}, err
+				}
+				out.Previous = env.CookbookVersions[in.Cookbook]
+				pins := make(map[string]string, len(env.CookbookVersions)+1)
+				for name, pin := range env.CookbookVersions {
+					pins[name] = pin
+				}
+				pins[in.Cookbook] = out.Constraint
+
+				// Solve representative run lists locally against the proposed pins
+				universe, err := api.GetUniverse(org)
+				if err != nil {
+					return nil, SetEnvironmentCookbookPinOutput{}, err
+				}
+				books := make(depsolve.Universe, len(universe.Books))
+				for name, book := range universe.Books {
+					books[name] = make(map[string]map[string]string, len(book.Versions))
+					for v, details := range book.Versions {
+						books[name][v] = details.Dependencies
+					}
+				}
+				var samples []chefapi.RunListSample
+				if len(in.RunLists) > 0 {
+					for _, runList := range in.RunLists {
+						samples = append(samples, chefapi.RunListSample{RunList: runList})
+					}
+				} else {
+					limit := in.MaxRunLists
+					if limit <= 0 {
+						limit = 10
+					}
+					if samples, err = api.EnvironmentRunLists(in.Environment, limit, org); err != nil {
+						return nil, SetEnvironmentCookbookPinOutput{}, err
+					}
+				}
+
+				// Run lists are expanded keeping recipe version pins (recipe[x@1.2.3]); the server check
+				// after applying depsolves the same expanded lists
+				out.Solves = true
+				out.RunLists = []PinSolveResult{}
+				var expanded [][]string
+				for _, sample := range samples {
+					if len(sample.RunList) == 0 {
+						continue
+					}
+					result := PinSolveResult{RunList: sample.RunList, Nodes: sample.Nodes}
+					recipes, err := api.ExpandRunListVersioned(sample.RunList, in.Environment, org)
+					if err == nil {
+						var roots, solution map[string]string
+						if roots, err = depsolve.Roots(recipes); err == nil {
+							if solution, err = depsolve.Solve(books, roots, pins); err == nil {
+								result.Solved = true
+								result.Version = solution[in.Cookbook]
+							}
+						}
+					}
+					if err != nil {
+						result.Error = err.Error()
+						out.Solves = false
+					}
+					out.RunLists = append(out.RunLists, result)
+					expanded = append(expanded, recipes)
+				}
+
+				if !out.Solves || out.Previous == out.Constraint {
+					return nil, out, nil
+				}
+				status, apply, err := planGate("setEnvironmentCookbookPin", in.Confirmation, out)
+				out.PlanStatus = status
+				if err != nil || !apply {
+					return nil, out, err
+				}
+				previous := env.CookbookVersions
+				env.CookbookVersions = pins
+				if _, err := api.UpdateEnvironment(changeFor(req, "setEnvironmentCookbookPin"), env, org); err != nil {
+					return nil, SetEnvironmentCookbookPinOutput{}, err
+				}
+				out.Applied = true
+
+				// The server's depsolver only sees stored pins, so check with it now that the pin is live
+				// and put the previous constraint back if any run list stopped solving
+				serverSolves := true
+				for i, result := range out.RunLists {
+					check := PinSolveResult{RunList: result.RunList, Nodes: result.Nodes}
+					solution, err := api.Depsolve(in.Environment, expanded[i], org)
+					if err != nil {
+						check.Error = err.Error()
+						serverSolves = false
+					} else {
+						check.Solved = true
+						check.Version = solution[in.Cookbook]
+					}
+					out.ServerCheck = append(out.ServerCheck, check)
+				}
+				if !serverSolves {
+					env.CookbookVersions = previous
+					if _, err := api.UpdateEnvironment(changeFor(req, "setEnvironmentCookbookPin"), env, org); err != nil {
+						return nil, SetEnvironmentCookbookPinOutput{}, fmt.Errorf("the Chef server depsolver rejected the new pin and restoring the previous constraint failed, so the new pin is still live: %w", err)
+					}
+					out.Applied = false
+					out.RolledBack = true
+				}
+				return nil, out, nil
+			})
+
+		// createRole and updateRole build, validate and diff the role the same way; only the starting
+		// point and the final API call differ
+		writeRole := func(req *mcp.CallToolRequest, in RoleWriteInput, create bool) (RoleWriteOutput, error) {
 			api, err := needAPI()
 			if err != nil {
-				return nil, GetEnvironmentOutput{}, err
+				return RoleWriteOutput{}, err
 			}
 
 			// Resolve organization
 			org := cfg.ResolveOrganization(getOrgString(in.Organization))
 			if org == "" {
-				return nil, GetEnvironmentOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				return RoleWriteOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+			}
+			if !chefNamePattern.MatchString(in.Name) {
+				return RoleWriteOutput{}, fmt.Errorf("invalid role name '%s'", in.Name)
+			}
+			// Attribute maps replace the current ones whole, and roles read through this server are redacted:
+			// a round-tripped placeholder would overwrite the real secret
+			if path, found := redact.FindMarker(in.DefaultAttributes); found {
+				return RoleWriteOutput{}, fmt.Errorf("defaultAttributes.%s holds a redaction placeholder: refusing to overwrite a secret with it, supply the real value", path)
+			}
+			if path, found := redact.FindMarker(in.OverrideAttributes); found {
+				return RoleWriteOutput{}, fmt.Errorf("overrideAttributes.%s holds a redaction placeholder: refusing to overwrite a secret with it, supply the real value", path)
 			}
 
-			environment, err := api.GetEnvironment(in.Name, org)
+			current, err := api.GetRole(in.Name, org)
+			switch {
+			case err == nil:
+				if create {
+					return RoleWriteOutput{}, fmt.Errorf("role '%s' already exists", in.Name)
+				}
+			case chefapi.IsNotFound(err):
+				if !create {
+					return RoleWriteOutput{}, fmt.Errorf("role '%s' does not exist", in.Name)
+				}
+				current = nil
+			default:
+				return RoleWriteOutput{}, err
+			}
+
+			role := &chef.Role{Name: in.Name, ChefType: "role", JsonClass: "Chef::Role", RunList: chef.RunList{}}
+			if current != nil {
+				role.Description = current.Description
+				role.RunList = current.RunList
+				role.EnvRunList = current.EnvRunList
+				role.DefaultAttributes = current.DefaultAttributes
+				role.OverrideAttributes = current.OverrideAttributes
+			}
+			if in.Description != nil {
+				role.Description = *in.Description
+			}
+			if in.DefaultAttributes != nil {
+				role.DefaultAttributes = in.DefaultAttributes
+			}
+			if in.OverrideAttributes != nil {
+				role.OverrideAttributes = in.OverrideAttributes
+			}
+
+			// Normalize run lists, collecting syntax errors and self references instead of failing on the first
+			out := RoleWriteOutput{Name: in.Name, Organization: org, Problems: []chefapi.RunListProblem{}}
+			normalize := func(items []string) []string {
+				normalized := make([]string, 0, len(items))
+				for _, item := range items {
+					n, err := chefapi.NormalizeRunList([]string{item})
+					if err != nil {
+						out.Problems = append(out.Problems, chefapi.RunListProblem{Item: item, Problem: err.Error()})
+						continue
+					}
+					if n[0] == "role["+in.Name+"]" {
+						out.Problems = append(out.Problems, chefapi.RunListProblem{Item: item, Problem: "a role cannot include itself"})
+						continue
+					}
+					normalized = append(normalized, n[0])
+				}
+				return normalized
+			}
+			if in.RunList != nil {
+				role.RunList = normalize(in.RunList)
+			}
+			if in.EnvRunLists != nil {
+				role.EnvRunList = make(chef.EnvRunList, len(in.EnvRunLists))
+				envs, err := api.ListEnvironments(org)
+				if err != nil {
+					return RoleWriteOutput{}, err
+				}
+				for env, items := range in.EnvRunLists {
+					if !slices.Contains(envs, env) {
+						out.Problems = append(out.Problems, chefapi.RunListProblem{Item: "envRunLists." + env, Problem: fmt.Sprintf("environment '%s' does not exist", env)})
+					}
+					role.EnvRunList[env] = normalize(items)
+				}
+			}
+
+			// Only entries the change introduces need to exist
+			var before, after []string
+			if current != nil {
+				before = append(before, current.RunList...)
+				for _, list := range current.EnvRunList {
+					before = append(before, list...)
+				}
+			}
+			after = append(after, role.RunList...)
+			for _, list := range role.EnvRunList {
+				after = append(after, list...)
+			}
+			added, _ := diff.StringSet(before, after)
+			problems, err := api.ValidateRunList(added, org)
 			if err != nil {
-				return nil, GetEnvironmentOutput{}, err
+				return RoleWriteOutput{}, err
 			}
-			return nil, GetEnvironmentOutput{Environment: environment, Organization: org}, nil
-		})
+			out.Problems = append(out.Problems, problems...)
+
+			var previous interface{}
+			if current != nil {
+				previous = current
+			}
+			out.Role = role
+			out.Changes = diff.Compare(previous, role, nil)
+			if len(out.Problems) > 0 || len(out.Changes) == 0 {
+				return out, nil
+			}
+			tool := "updateRole"
+			if create {
+				tool = "createRole"
+			}
+			status, apply, err := planGate(tool, in.Confirmation, out)
+			out.PlanStatus = status
+			if err != nil || !apply {
+				return out, err
+			}
+			if create {
+				err = api.CreateRole(changeFor(req, tool), role, org)
+			} else {
+				_, err = api.UpdateRole(changeFor(req, tool), role, org)
+			}
+			if err != nil {
+				return RoleWriteOutput{}, err
+			}
+			out.Applied = true
+			return out, nil
+		}
+
+		// createRole
+		mcp.AddTool(server, &mcp.Tool{Name: "createRole", Description: "Create a role from a definition (description, run list, env run lists, default/override attributes) after validating run-list syntax and referenced roles, recipes and environments - returns a plan with a confirmation; call again with the confirmation to apply", Annotations: writeTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in RoleWriteInput) (*mcp.CallToolResult, RoleWriteOutput, error) {
+				out, err := writeRole(req, in, true)
+				return nil, out, err
+			})
+
+		// updateRole
+		mcp.AddTool(server, &mcp.Tool{Name: "updateRole", Description: "Update a role, replacing only the fields given, after validating run-list syntax and referenced roles, recipes and environments - returns a plan with a structured diff and a confirmation; call again with the confirmation to apply", Annotations: destructiveTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in RoleWriteInput) (*mcp.CallToolResult, RoleWriteOutput, error) {
+				out, err := writeRole(req, in, false)
+				return nil, out, err
+			})
+
+		// decommissionNode
+		mcp.AddTool(server, &mcp.Tool{Name: "decommissionNode", Description: "Delete a node together with its API client: call without confirmation to preview the node, client and ACL and get a confirmation, then call again with it to delete (optionally archiving the node JSON first)", Annotations: destructiveTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in DecommissionNodeInput) (*mcp.CallToolResult, DecommissionNodeOutput, error) {
+				api, err := needAPI()
+				if err != nil {
+					return nil, DecommissionNodeOutput{}, err
+				}
+
+				// Resolve organization
+				org := cfg.ResolveOrganization(getOrgString(in.Organization))
+				if org == "" {
+					return nil, DecommissionNodeOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				}
+				clientName := getOrgString(in.ClientName)
+				if clientName == "" {
+					clientName = in.NodeName
+				}
+				if in.Archive && cfg.ArchiveDir == "" {
+					return nil, DecommissionNodeOutput{}, fmt.Errorf("archiving requires CHEF_ARCHIVE_DIR to be set")
+				}
+
+				node, err := api.GetNode(in.NodeName, org)
+				if err != nil {
+					return nil, DecommissionNodeOutput{}, err
+				}
+				client, err := api.GetClient(clientName, org)
+				if err != nil {
+					if !chefapi.IsNotFound(err) {
+						return nil, DecommissionNodeOutput{}, err
+					}
+					client = nil
+				}
+				acl, err := api.GetACL("node", in.NodeName, org)
+				if err != nil {
+					return nil, DecommissionNodeOutput{}, err
+				}
+
+				out := DecommissionNodeOutput{
+					NodeName:     in.NodeName,
+					ClientName:   clientName,
+					Organization: org,
+					Environment:  node.Environment,
+					RunList:      node.RunList,
+					PolicyGroup:  node.PolicyGroup,
+					PolicyName:   node.PolicyName,
+					ClientExists: client != nil,
+					ACL:          acl,
+					Archive:      in.Archive,
+				}
+				if client != nil {
+					out.Validator = client.Validator
+				}
+				// Archiving is part of the plan so a confirmation cannot be applied with a different choice
+				if in.Archive {
+					out.ArchiveDir = filepath.Join(cfg.ArchiveDir, org)
+				}
+
+				status, apply, err := planGate("decommissionNode", in.Confirmation, out)
+				out.PlanStatus = status
+				if err != nil || !apply {
+					return nil, out, err
+				}
+
+				if in.Archive {
+					if out.ArchivePath, err = archiveNode(cfg.ArchiveDir, org, node, client, acl); err != nil {
+						return nil, DecommissionNodeOutput{}, err
+					}
+				}
+				if err := api.DeleteNode(changeFor(req, "decommissionNode"), in.NodeName, org); err != nil {
+					return nil, DecommissionNodeOutput{}, err
+				}
+				out.NodeDeleted = true
+				out.Applied = true
+				if client != nil {
+					if err := api.DeleteClient(changeFor(req, "decommissionNode"), clientName, org); err != nil {
+						return nil, out, fmt.Errorf("node '%s' deleted but deleting client '%s' failed: %w", in.NodeName, clientName, err)
+					}
+					out.ClientDeleted = true
+				}
+				return nil, out, nil
+			})
+
+		// revertChange
+		mcp.AddTool(server, &mcp.Tool{Name: "revertChange", Description: "Restore the node, role, environment or data bag item snapshot stored in a change journal entry (re-creating the object if it was deleted) - returns a plan with the diff and a confirmation; call again with the confirmation to apply", Annotations: destructiveTool},
+			func(ctx context.Context, req *mcp.CallToolRequest, in RevertChangeInput) (*mcp.CallToolResult, RevertChangeOutput, error) {
+				api, err := needAPI()
+				if err != nil {
+					return nil, RevertChangeOutput{}, err
+				}
+
+				// Resolve organization
+				org := cfg.ResolveOrganization(getOrgString(in.Organization))
+				if org == "" {
+					return nil, RevertChangeOutput{}, fmt.Errorf("organization must be specified or CHEF_DEFAULT_ORG must be set")
+				}
+
+				entry, err := api.Journal.Get(org, in.EntryID)
+				if err != nil {
+					return nil, RevertChangeOutput{}, err
+				}
+				switch entry.Kind {
+				case chefapi.KindNode, chefapi.KindRole, chefapi.KindEnvironment, chefapi.KindDataBagItem:
+				default:
+					return nil, RevertChangeOutput{}, fmt.Errorf("reverting %s changes is not supported", entry.Kind)
+				}
+				if !entry.Existed {
+					return nil, RevertChangeOutput{}, fmt.Errorf("%s '%s' did not exist before this change; there is no snapshot to restore", entry.Kind, entry.Name)
+				}
+				var snapshot interface{}
+				if err := json.Unmarshal(entry.Object, &snapshot); err != nil {
+					return nil, RevertChangeOutput{}, fmt.Errorf("decode journal snapshot: %w", err)
+				}
+
+				out := RevertChangeOutput{Organization: org}
+				current, err := api.GetObject(entry.Kind, entry.Name, org)
+				switch {
+				case err == nil:
+					out.Exists = true
+				case chefapi.IsNotFound(err):
+					current = nil
+				default:
+					return nil, RevertChangeOutput{}, err
+				}
+
+				// Node automatic attributes are kept as they are, so they are left out of the diff
+				var ignore []string
+				if entry.Kind == chefapi.KindNode && out.Exists {
+					ignore = []string{"automatic"}
+				}
+				out.Changes = diff.Compare(current, snapshot, ignore)
+				object := entry.Object
+				entry.Object = nil
+				out.Entry = entry
+				if len(out.Changes) == 0 {
+					return nil, out, nil
+				}
+
+				status, apply, err := planGate("revertChange", in.Confirmation, out)
+				out.PlanStatus = status
+				if err != nil || !apply {
+					return nil, out, err
+				}
+				if err := api.RestoreObject(changeFor(req, "revertChange"), entry.Kind, entry.Name, object, org); err != nil {
+					return nil, RevertChangeOutput{}, err
+				}
+				out.Applied = true
+				return nil, out, nil
+			})
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -589,3 +3162,243 @@ func main() {
 		log.Printf("mcp server stopped (EOF)")
 	}
 }
+
+// diffRunLists reports entries added to or removed from run list b compared with a,
+// and whether the shared entries were reordered.
+func diffRunLists(a, b []string) RunListDiff {
+	added, removed := diff.StringSet(a, b)
+	return RunListDiff{
+		Added:        added,
+		Removed:      removed,
+		OrderChanged: len(added) == 0 && len(removed) == 0 && strings.Join(a, ",") != strings.Join(b, ","),
+	}
+}
+
+// empty reports whether the run lists compared equal
+func (d RunListDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && !d.OrderChanged
+}
+
+// editRunList applies an add, remove or replace action to a copy of a normalized run list
+func editRunList(current []string, action string, items []string, position *int, target *string) ([]string, error) {
+	if len(items) == 0 && action != "replace" {
+		return nil, fmt.Errorf("items must not be empty")
+	}
+	out := make([]string, 0, len(current)+len(items))
+	switch action {
+	case "add":
+		existing := make(map[string]bool, len(current))
+		for _, item := range current {
+			existing[item] = true
+		}
+		var adding []string
+		for _, item := range items {
+			if !existing[item] {
+				existing[item] = true
+				adding = append(adding, item)
+			}
+		}
+		at := len(current)
+		if position != nil {
+			if *position < 0 || *position > len(current) {
+				return nil, fmt.Errorf("position %d is out of range (run list has %d entries)", *position, len(current))
+			}
+			at = *position
+		}
+		out = append(out, current[:at]...)
+		out = append(out, adding...)
+		out = append(out, current[at:]...)
+	case "remove":
+		removing := make(map[string]bool, len(items))
+		for _, item := range items {
+			removing[item] = true
+		}
+		for _, item := range current {
+			if !removing[item] {
+				out = append(out, item)
+			}
+		}
+	case "replace":
+		if target == nil {
+			return append(out, items...), nil
+		}
+		normalized, err := chefapi.NormalizeRunList([]string{*target})
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, item := range current {
+			if item == normalized[0] && !found {
+				found = true
+				out = append(out, items...)
+				continue
+			}
+			out = append(out, item)
+		}
+		if !found {
+			return nil, fmt.Errorf("run list does not contain '%s'", *target)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported action '%s': expected add, remove or replace", action)
+	}
+	return out, nil
+}
+
+// chefNamePattern matches valid data bag and item names
+var chefNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+// dataBagEncrypted reports whether a bag holds encrypted data: it has a secret configured, is a
+// chef-vault, or its first regular item is encrypted
+func dataBagEncrypted(api *chefapi.ChefAPI, cfg *config.Config, bag, org string) (bool, error) {
+	if _, ok := cfg.DataBagSecrets[bag]; ok {
+		return true, nil
+	}
+	items, err := api.ListDataBagItems(bag, org)
+	if err != nil {
+		if chefapi.IsNotFound(err) {
+			return false, fmt.Errorf("data bag '%s' does not exist", bag)
+		}
+		return false, err
+	}
+	sort.Strings(items)
+	for _, name := range items {
+		if strings.HasSuffix(name, databag.VaultKeysSuffix) && slices.Contains(items, strings.TrimSuffix(name, databag.VaultKeysSuffix)) {
+			return true, nil
+		}
+	}
+	for _, name := range items {
+		if name == databag.SchemaItem {
+			continue
+		}
+		item, err := api.GetDataBagItem(bag, name, org)
+		if err != nil {
+			return false, err
+		}
+		fields, _ := (*item).(map[string]interface{})
+		return fields != nil && databag.IsEncrypted(fields), nil
+	}
+	return false, nil
+}
+
+// dataBagSchema returns the JSON Schema for a bag, preferring a configured file over the bag's
+// schema item. A nil schema means the bag is not validated.
+func dataBagSchema(api *chefapi.ChefAPI, cfg *config.Config, bag, org string) (*jsonschema.Resolved, string, error) {
+	if path, ok := cfg.DataBagSchemas[bag]; ok {
+		schema, err := databag.LoadSchema(path)
+		return schema, "file " + path, err
+	}
+	item, err := api.GetDataBagItem(bag, databag.SchemaItem, org)
+	if err != nil {
+		if chefapi.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	fields, _ := (*item).(map[string]interface{})
+	schema, err := databag.SchemaFromItem(fields)
+	return schema, "item " + bag + "/" + databag.SchemaItem, err
+}
+
+// changeFor describes a mutation made by a tool call for the change journal; the caller is the MCP client
+func changeFor(req *mcp.CallToolRequest, tool string) chefapi.Change {
+	caller := "unknown"
+	if req != nil && req.Session != nil {
+		if params := req.Session.InitializeParams(); params != nil && params.ClientInfo != nil {
+			caller = params.ClientInfo.Name
+			if params.ClientInfo.Version != "" {
+				caller += "/" + params.ClientInfo.Version
+			}
+		}
+	}
+	return chefapi.Change{Tool: tool, Caller: caller}
+}
+
+// archiveNode writes the node, its client and ACL to <dir>/<org>/<node>-<timestamp>.json and returns the path
+func archiveNode(dir, org string, node *chef.Node, client *chef.ApiClient, acl chef.ACL) (string, error) {
+	orgDir := filepath.Join(dir, org)
+	if err := os.MkdirAll(orgDir, 0o700); err != nil {
+		return "", fmt.Errorf("create archive directory: %w", err)
+	}
+	now := time.Now().UTC()
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"archivedAt": now,
+		"node":       node,
+		"client":     client,
+		"acl":        acl,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(orgDir, fmt.Sprintf("%s-%s.json", node.Name, now.Format("20060102T150405Z")))
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("write node archive: %w", err)
+	}
+	return path, nil
+}
+
+// getVaultKeys fetches the "<item>_keys" item of a chef-vault item
+func getVaultKeys(api *chefapi.ChefAPI, vault, item, org string) (map[string]interface{}, error) {
+	keysItem, err := api.GetDataBagItem(vault, item+databag.VaultKeysSuffix, org)
+	if err != nil {
+		return nil, fmt.Errorf("get vault keys for '%s/%s': %w", vault, item, err)
+	}
+	keys, _ := (*keysItem).(map[string]interface{})
+	if keys == nil || !databag.IsVaultKeys(keys) {
+		return nil, fmt.Errorf("'%s/%s' is not a chef-vault item", vault, item)
+	}
+	return keys, nil
+}
+
+// Tool annotations advertised to clients so they can tell read-only tools from mutating ones.
+// writeTool is only for purely additive tools; anything that overwrites, replaces or deletes an
+// existing object is a destructiveTool.
+var (
+	readOnlyTool    = &mcp.ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+	writeTool       = &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)}
+	destructiveTool = &mcp.ToolAnnotations{DestructiveHint: boolPtr(true)}
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// plaintextMetaKey marks a result whose secrets were deliberately returned in plaintext
+const plaintextMetaKey = "chef-server-mcp/plaintext"
+
+// plaintextResult exempts a tool result from redaction; only used once the caller asked for
+// plaintext and the server permits it
+func plaintextResult() *mcp.CallToolResult {
+	return &mcp.CallToolResult{Meta: mcp.Meta{plaintextMetaKey: true}}
+}
+
+// redactMiddleware passes the structured content and every text block of tool results through
+// the redaction engine. Text blocks are redacted on their own: the SDK encodes them from the typed
+// output, so they do not match the re-encoded structured content byte for byte.
+func redactMiddleware(engine *redact.Engine) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			res, ok := result.(*mcp.CallToolResult)
+			if err != nil || !ok || res == nil || res.Meta[plaintextMetaKey] == true {
+				return result, err
+			}
+			if res.StructuredContent != nil {
+				original, err := json.Marshal(res.StructuredContent)
+				if err != nil {
+					return nil, fmt.Errorf("failed to redact tool output: %w", err)
+				}
+				redacted, err := engine.RedactJSON(original)
+				if err != nil {
+					return nil, fmt.Errorf("failed to redact tool output: %w", err)
+				}
+				res.StructuredContent = json.RawMessage(redacted)
+			}
+			for _, c := range res.Content {
+				if text, ok := c.(*mcp.TextContent); ok {
+					text.Text = engine.RedactText(text.Text)
+				}
+			}
+			return res, nil
+		}
+	}
+}
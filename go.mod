@@ -4,10 +4,8 @@ go 1.23.0
 
 require (
 	github.com/go-chef/chef v0.30.1
+	github.com/google/jsonschema-go v0.2.3
 	github.com/modelcontextprotocol/go-sdk v0.5.0
 )
 
-require (
-	github.com/google/jsonschema-go v0.2.3 // indirect
-	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
-)
+require github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
@@ -0,0 +1,204 @@
+package chefapi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-chef/chef"
+)
+
+// ACLPermissions lists the Chef ACL permissions in the order the server reports them
+var ACLPermissions = []string{"create", "read", "update", "delete", "grant"}
+
+// aclSubkinds maps friendly object types to the Chef API collection holding their ACL
+var aclSubkinds = map[string]string{
+	"node":             "nodes",
+	"role":             "roles",
+	"environment":      "environments",
+	"dataBag":          "data",
+	"cookbook":         "cookbooks",
+	"cookbookArtifact": "cookbook_artifacts",
+	"container":        "containers",
+	"client":           "clients",
+	"group":            "groups",
+	"policy":           "policies",
+	"policyGroup":      "policy_groups",
+}
+
+// Principal is a user or client granted access, either directly or through a chain of groups
+type Principal struct {
+	Name string   `json:"name"`
+	Type string   `json:"type"`          // "user", "client" or "actor" (servers that do not split actors)
+	Via  []string `json:"via,omitempty"` // Group chain granting access; empty for direct grants
+}
+
+// ListGroups returns a sorted slice of group names from the specified organization
+func (api *ChefAPI) ListGroups(organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	groupsMap, err := client.Groups.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(groupsMap))
+	for name := range groupsMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetGroup fetches a single group with its direct members from the specified organization
+func (api *ChefAPI) GetGroup(name, organization string) (*chef.Group, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := client.Groups.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// ExpandGroup returns every user and client that belongs to the group, following nested groups.
+// Each principal is reported once, through the shortest group chain.
+func (api *ChefAPI) ExpandGroup(name, organization string) ([]Principal, error) {
+	return newGroupExpander(api, organization).expand(name)
+}
+
+// GetACL fetches the ACL of an object. kind is one of node, role, environment, dataBag, cookbook,
+// cookbookArtifact, container, client, group, policy or policyGroup.
+func (api *ChefAPI) GetACL(kind, name, organization string) (chef.ACL, error) {
+	subkind, ok := aclSubkinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported ACL object type '%s'", kind)
+	}
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ACLs.Get(subkind, name)
+}
+
+// EffectivePermissions resolves an object's ACL into the principals holding each permission,
+// expanding group grants recursively
+func (api *ChefAPI) EffectivePermissions(kind, name, organization string) (map[string][]Principal, error) {
+	acl, err := api.GetACL(kind, name, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	expander := newGroupExpander(api, organization)
+	perms := make(map[string][]Principal, len(ACLPermissions))
+	for _, perm := range ACLPermissions {
+		items, ok := acl[perm]
+		if !ok {
+			continue
+		}
+		var principals []Principal
+		for _, u := range items.Users {
+			principals = append(principals, Principal{Name: u, Type: "user"})
+		}
+		for _, c := range items.Clients {
+			principals = append(principals, Principal{Name: c, Type: "client"})
+		}
+		// Older servers only report actors (users and clients combined)
+		if len(items.Users) == 0 && len(items.Clients) == 0 {
+			for _, a := range items.Actors {
+				principals = append(principals, Principal{Name: a, Type: "actor"})
+			}
+		}
+		for _, g := range items.Groups {
+			members, err := expander.expand(g)
+			if err != nil {
+				return nil, fmt.Errorf("expand group '%s': %w", g, err)
+			}
+			principals = append(principals, members...)
+		}
+		perms[perm] = dedupePrincipals(principals)
+	}
+	return perms, nil
+}
+
+// groupExpander walks nested groups, caching group lookups for the lifetime of one request
+type groupExpander struct {
+	api          *ChefAPI
+	organization string
+	groups       map[string]*chef.Group
+}
+
+func newGroupExpander(api *ChefAPI, organization string) *groupExpander {
+	return &groupExpander{api: api, organization: organization, groups: make(map[string]*chef.Group)}
+}
+
+func (e *groupExpander) get(name string) (*chef.Group, error) {
+	if g, ok := e.groups[name]; ok {
+		return g, nil
+	}
+	g, err := e.api.GetGroup(name, e.organization)
+	if err != nil {
+		return nil, err
+	}
+	e.groups[name] = g
+	return g, nil
+}
+
+// expand performs a breadth-first walk so that each principal is reported via its shortest chain
+func (e *groupExpander) expand(root string) ([]Principal, error) {
+	type entry struct {
+		name string
+		via  []string
+	}
+	visited := map[string]bool{root: true}
+	queue := []entry{{name: root, via: []string{root}}}
+	var principals []Principal
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		g, err := e.get(cur.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range g.Users {
+			principals = append(principals, Principal{Name: u, Type: "user", Via: cur.via})
+		}
+		for _, c := range g.Clients {
+			principals = append(principals, Principal{Name: c, Type: "client", Via: cur.via})
+		}
+		if len(g.Users) == 0 && len(g.Clients) == 0 {
+			for _, a := range g.Actors {
+				principals = append(principals, Principal{Name: a, Type: "actor", Via: cur.via})
+			}
+		}
+		for _, nested := range g.Groups {
+			if visited[nested] {
+				continue
+			}
+			visited[nested] = true
+			via := append(append([]string{}, cur.via...), nested)
+			queue = append(queue, entry{name: nested, via: via})
+		}
+	}
+	return dedupePrincipals(principals), nil
+}
+
+// dedupePrincipals keeps the first occurrence of each principal, preserving order
+func dedupePrincipals(in []Principal) []Principal {
+	seen := make(map[string]bool, len(in))
+	out := make([]Principal, 0, len(in))
+	for _, p := range in {
+		key := p.Type + "/" + p.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, p)
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package chefapi
+
+import (
+	"sort"
+
+	"github.com/go-chef/chef"
+)
+
+// ListOrganizationMembers returns the sorted user names associated with the specified organization
+func (api *ChefAPI) ListOrganizationMembers(organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := client.Associations.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.User.Username)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListInvitations returns the pending association requests (invitations) of the specified organization
+func (api *ChefAPI) ListInvitations(organization string) ([]chef.Invite, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	invites, err := client.Associations.ListInvites()
+	if err != nil {
+		return nil, err
+	}
+	if invites == nil {
+		return []chef.Invite{}, nil
+	}
+	return invites, nil
+}
+
+// ListOrganizationAdmins returns the set of users in the organization's admins group, including nested groups
+func (api *ChefAPI) ListOrganizationAdmins(organization string) (map[string]bool, error) {
+	members, err := api.ExpandGroup("admins", organization)
+	if err != nil {
+		return nil, err
+	}
+	admins := make(map[string]bool, len(members))
+	for _, m := range members {
+		if m.Type == "user" || m.Type == "actor" {
+			admins[m.Name] = true
+		}
+	}
+	return admins, nil
+}
@@ -1,22 +1,32 @@
 package chefapi
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/go-chef/chef"
+
+	"github.com/aknarts/chef-server-mcp/internal/journal"
 )
 
 // ChefAPI wraps the go-chef client and provides multi-organization support
 type ChefAPI struct {
-	BaseURL     string
-	Name        string
-	KeyMaterial string
-	clients     map[string]*chef.Client // Cache clients per organization
+	BaseURL      string
+	Name         string
+	KeyMaterial  string
+	ReadOnly     bool                    // Refuse every request that could modify the server
+	Journal      *journal.Journal        // Receives a snapshot of every object before it is modified
+	mu           sync.Mutex              // Guards clients and serverClient for concurrent tool calls
+	clients      map[string]*chef.Client // Cache clients per organization
+	serverClient *chef.Client            // Client for server-level endpoints (no organization)
 }
 
-// NewChefAPI initializes a ChefAPI client
+// NewChefAPI initializes a ChefAPI client in read-only mode; clear ReadOnly before first use to allow writes
 // keyPathOrInline can be a filesystem path to the PEM private key or the inline PEM contents themselves.
 // serverURL should be the base Chef server URL without organization path
 func NewChefAPI(name, keyPathOrInline, serverURL string) (*ChefAPI, error) {
@@ -38,6 +48,7 @@ func NewChefAPI(name, keyPathOrInline, serverURL string) (*ChefAPI, error) {
 		BaseURL:     baseURL,
 		Name:        name,
 		KeyMaterial: keyMaterial,
+		ReadOnly:    true,
 		clients:     make(map[string]*chef.Client),
 	}, nil
 }
@@ -48,6 +59,9 @@ func (api *ChefAPI) getClientForOrg(organization string) (*chef.Client, error) {
 		return nil, fmt.Errorf("organization cannot be empty")
 	}
 
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
 	// Check if we already have a client for this organization
 	if client, exists := api.clients[organization]; exists {
 		return client, nil
@@ -56,9 +70,10 @@ func (api *ChefAPI) getClientForOrg(organization string) (*chef.Client, error) {
 	// Create new client for this organization
 	orgURL := api.BaseURL + "organizations/" + organization + "/"
 	client, err := chef.NewClient(&chef.Config{
-		Name:    api.Name,
-		Key:     api.KeyMaterial,
-		BaseURL: orgURL,
+		Name:         api.Name,
+		Key:          api.KeyMaterial,
+		BaseURL:      orgURL,
+		RoundTripper: api.roundTripper(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("init chef client for org '%s': %w", organization, err)
@@ -69,6 +84,42 @@ func (api *ChefAPI) getClientForOrg(organization string) (*chef.Client, error) {
 	return client, nil
 }
 
+// getServerClient returns a Chef client rooted at the server base URL for server-level endpoints
+func (api *ChefAPI) getServerClient() (*chef.Client, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if api.serverClient != nil {
+		return api.serverClient, nil
+	}
+
+	client, err := chef.NewClient(&chef.Config{
+		Name:         api.Name,
+		Key:          api.KeyMaterial,
+		BaseURL:      api.BaseURL,
+		RoundTripper: api.roundTripper(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("init chef server client: %w", err)
+	}
+
+	api.serverClient = client
+	return client, nil
+}
+
+// getJSON performs a signed GET for endpoints not covered by go-chef and decodes the JSON response into v
+func getJSON(client *chef.Client, path string, v interface{}) error {
+	req, err := client.NewRequest("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req, v)
+	if res != nil {
+		defer res.Body.Close()
+	}
+	return err
+}
+
 // ListNodes returns a list of node names from the Chef server for the specified organization
 func (api *ChefAPI) ListNodes(organization string) ([]string, error) {
 	client, err := api.getClientForOrg(organization)
@@ -101,6 +152,56 @@ func (api *ChefAPI) GetNode(name, organization string) (*chef.Node, error) {
 	return &n, nil
 }
 
+// CreateNode creates a node in the specified organization
+func (api *ChefAPI) CreateNode(change Change, node chef.Node, organization string) error {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return err
+	}
+	if err := api.snapshot(change, journal.ActionCreate, KindNode, node.Name, organization, func() (interface{}, error) {
+		return api.GetNode(node.Name, organization)
+	}); err != nil {
+		return err
+	}
+
+	_, err = client.Nodes.Post(node)
+	return err
+}
+
+// UpdateNode replaces a node on the Chef server for the specified organization
+func (api *ChefAPI) UpdateNode(change Change, node chef.Node, organization string) (*chef.Node, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.snapshot(change, journal.ActionUpdate, KindNode, node.Name, organization, func() (interface{}, error) {
+		return api.GetNode(node.Name, organization)
+	}); err != nil {
+		return nil, err
+	}
+
+	n, err := client.Nodes.Put(node)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// DeleteNode removes a node from the specified organization
+func (api *ChefAPI) DeleteNode(change Change, name, organization string) error {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return err
+	}
+	if err := api.snapshot(change, journal.ActionDelete, KindNode, name, organization, func() (interface{}, error) {
+		return api.GetNode(name, organization)
+	}); err != nil {
+		return err
+	}
+
+	return client.Nodes.Delete(name)
+}
+
 // ListRoles returns a slice of role names from the specified organization
 func (api *ChefAPI) ListRoles(organization string) ([]string, error) {
 	client, err := api.getClientForOrg(organization)
@@ -132,6 +233,37 @@ func (api *ChefAPI) GetRole(name, organization string) (*chef.Role, error) {
 	return client.Roles.Get(name)
 }
 
+// CreateRole creates a new role in the specified organization
+func (api *ChefAPI) CreateRole(change Change, role *chef.Role, organization string) error {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return err
+	}
+	if err := api.snapshot(change, journal.ActionCreate, KindRole, role.Name, organization, func() (interface{}, error) {
+		return api.GetRole(role.Name, organization)
+	}); err != nil {
+		return err
+	}
+
+	_, err = client.Roles.Create(role)
+	return err
+}
+
+// UpdateRole replaces an existing role in the specified organization
+func (api *ChefAPI) UpdateRole(change Change, role *chef.Role, organization string) (*chef.Role, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.snapshot(change, journal.ActionUpdate, KindRole, role.Name, organization, func() (interface{}, error) {
+		return api.GetRole(role.Name, organization)
+	}); err != nil {
+		return nil, err
+	}
+
+	return client.Roles.Put(role)
+}
+
 // ListUsers returns a slice of user names from the specified organization
 func (api *ChefAPI) ListUsers(organization string) ([]string, error) {
 	client, err := api.getClientForOrg(organization)
@@ -184,6 +316,57 @@ func (api *ChefAPI) SearchJSON(index, statement, organization string) (chef.JSea
 	return client.Search.ExecJSON(index, statement)
 }
 
+// partialSearchRows is the page size PartialSearch requests
+const partialSearchRows = 1000
+
+// PartialSearch executes a Chef partial search returning only the requested fields for each row.
+// fields maps result keys to attribute paths, e.g. {"ip": {"ipaddress"}}.
+// Pages are requested here rather than through go-chef's PartialExecJSON, which prints page
+// errors to stdout (the MCP stdio transport) and does not URL-encode the query.
+func (api *ChefAPI) PartialSearch(index, statement string, fields map[string][]string, organization string) ([]map[string]interface{}, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []map[string]interface{}{}
+	for start := 0; ; {
+		query := url.Values{
+			"q":     {statement},
+			"sort":  {"X_CHEF_id_CHEF_X asc"},
+			"start": {strconv.Itoa(start)},
+			"rows":  {strconv.Itoa(partialSearchRows)},
+		}
+		body, err := chef.JSONReader(fields)
+		if err != nil {
+			return nil, err
+		}
+		req, err := client.NewRequest("POST", "search/"+url.PathEscape(index)+"?"+query.Encode(), body)
+		if err != nil {
+			return nil, err
+		}
+		var page chef.JSearchResult
+		res, err := client.Do(req, &page)
+		if res != nil {
+			res.Body.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range page.Rows {
+			var data map[string]interface{}
+			if err := json.Unmarshal(r.Data, &data); err != nil {
+				return nil, fmt.Errorf("decode partial search row: %w", err)
+			}
+			rows = append(rows, data)
+		}
+		start += len(page.Rows)
+		if len(page.Rows) == 0 || start >= page.Total {
+			return rows, nil
+		}
+	}
+}
+
 // GetOrganization returns organization details for the specified organization
 func (api *ChefAPI) GetOrganization(organization string) (*chef.Organization, error) {
 	client, err := api.getClientForOrg(organization)
@@ -212,6 +395,24 @@ func (api *ChefAPI) ListCookbooks(organization string) (chef.CookbookListResult,
 	return cookbooks, nil
 }
 
+// ListCookbookVersions returns every uploaded version of a cookbook, newest first
+func (api *ChefAPI) ListCookbookVersions(name, organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	cookbooks, err := client.Cookbooks.GetAvailableVersions(name, "all")
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(cookbooks[name].Versions))
+	for _, v := range cookbooks[name].Versions {
+		versions = append(versions, v.Version)
+	}
+	return versions, nil
+}
+
 // GetCookbook returns a cookbook with the specified version from the specified organization
 // If version is empty or "_latest", it will get the latest version
 func (api *ChefAPI) GetCookbook(name, version, organization string) (*chef.Cookbook, error) {
@@ -288,6 +489,53 @@ func (api *ChefAPI) GetDataBagItem(bagName, itemName, organization string) (*che
 	return &item, nil
 }
 
+// CreateDataBag creates an empty data bag in the specified organization
+func (api *ChefAPI) CreateDataBag(change Change, name, organization string) error {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return err
+	}
+	if err := api.snapshot(change, journal.ActionCreate, KindDataBag, name, organization, func() (interface{}, error) {
+		return api.ListDataBagItems(name, organization)
+	}); err != nil {
+		return err
+	}
+
+	_, err = client.DataBags.Create(&chef.DataBag{Name: name})
+	return err
+}
+
+// CreateDataBagItem adds a new item to a data bag in the specified organization
+func (api *ChefAPI) CreateDataBagItem(change Change, bagName string, item map[string]interface{}, organization string) error {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return err
+	}
+	itemName, _ := item["id"].(string)
+	if err := api.snapshot(change, journal.ActionCreate, KindDataBagItem, ItemName(bagName, itemName), organization, func() (interface{}, error) {
+		return api.GetDataBagItem(bagName, itemName, organization)
+	}); err != nil {
+		return err
+	}
+
+	return client.DataBags.CreateItem(bagName, item)
+}
+
+// UpdateDataBagItem replaces an existing data bag item in the specified organization
+func (api *ChefAPI) UpdateDataBagItem(change Change, bagName, itemName string, item map[string]interface{}, organization string) error {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return err
+	}
+	if err := api.snapshot(change, journal.ActionUpdate, KindDataBagItem, ItemName(bagName, itemName), organization, func() (interface{}, error) {
+		return api.GetDataBagItem(bagName, itemName, organization)
+	}); err != nil {
+		return err
+	}
+
+	return client.DataBags.UpdateItem(bagName, itemName, item)
+}
+
 // ListEnvironments returns a list of environment names from the specified organization
 func (api *ChefAPI) ListEnvironments(organization string) ([]string, error) {
 	client, err := api.getClientForOrg(organization)
@@ -323,6 +571,70 @@ func (api *ChefAPI) GetEnvironment(name, organization string) (*chef.Environment
 	return env, nil
 }
 
+// CreateEnvironment creates an environment in the specified organization
+func (api *ChefAPI) CreateEnvironment(change Change, env *chef.Environment, organization string) error {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return err
+	}
+	if err := api.snapshot(change, journal.ActionCreate, KindEnvironment, env.Name, organization, func() (interface{}, error) {
+		return api.GetEnvironment(env.Name, organization)
+	}); err != nil {
+		return err
+	}
+
+	_, err = client.Environments.Create(env)
+	return err
+}
+
+// UpdateEnvironment replaces an environment on the Chef server for the specified organization
+func (api *ChefAPI) UpdateEnvironment(change Change, env *chef.Environment, organization string) (*chef.Environment, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.snapshot(change, journal.ActionUpdate, KindEnvironment, env.Name, organization, func() (interface{}, error) {
+		return api.GetEnvironment(env.Name, organization)
+	}); err != nil {
+		return nil, err
+	}
+
+	return client.Environments.Put(env)
+}
+
+// Depsolve asks the Chef server which cookbook versions a run list resolves to in an environment,
+// returning cookbook name -> version
+func (api *ChefAPI) Depsolve(environment string, runList []string, organization string) (map[string]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := chef.JSONReader(map[string][]string{"run_list": runList})
+	if err != nil {
+		return nil, err
+	}
+	req, err := client.NewRequest("POST", "environments/"+environment+"/cookbook_versions", body)
+	if err != nil {
+		return nil, err
+	}
+	var cookbooks map[string]struct {
+		Version string `json:"version"`
+	}
+	res, err := client.Do(req, &cookbooks)
+	if res != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string, len(cookbooks))
+	for name, cb := range cookbooks {
+		versions[name] = cb.Version
+	}
+	return versions, nil
+}
+
 // ensureTrailingSlash appends a slash if missing (so url.ResolveReference treats BaseURL as a directory path)
 func ensureTrailingSlash(s string) string {
 	if s == "" || strings.HasSuffix(s, "/") {
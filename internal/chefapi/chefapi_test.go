@@ -0,0 +1,138 @@
+package chefapi
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newTestAPI returns a write-mode client for a test server running handler
+func newTestAPI(t *testing.T, handler http.Handler) *ChefAPI {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	api, err := NewChefAPI("admin", string(keyPEM), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.ReadOnly = false
+	return api
+}
+
+// searchServer serves a node partial search over total rows named node0..nodeN, failing the page
+// starting at failAt (if positive)
+type searchServer struct {
+	total, failAt int
+	queries       []string
+	bodies        []string
+}
+
+func (s *searchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/organizations/acme/search/node" {
+		http.NotFound(w, r)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(body))
+	s.queries = append(s.queries, r.URL.Query().Get("q"))
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+	rows, _ := strconv.Atoi(r.URL.Query().Get("rows"))
+	if s.failAt > 0 && start == s.failAt {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":["search failed"]}`))
+		return
+	}
+
+	type row struct {
+		URL  string                 `json:"url"`
+		Data map[string]interface{} `json:"data"`
+	}
+	page := []row{}
+	for i := start; i < start+rows && i < s.total; i++ {
+		page = append(page, row{URL: "/nodes/node" + strconv.Itoa(i), Data: map[string]interface{}{"name": fmt.Sprintf("node%d", i)}})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"total": s.total, "start": start, "rows": page})
+}
+
+func TestPartialSearchPages(t *testing.T) {
+	tests := []struct {
+		total, requests int
+	}{
+		{0, 1},
+		{1, 1},
+		{partialSearchRows, 1},
+		{partialSearchRows + 1, 2},
+		{2*partialSearchRows + 5, 3},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.total), func(t *testing.T) {
+			srv := &searchServer{total: tt.total}
+			api := newTestAPI(t, srv)
+			rows, err := api.PartialSearch("node", "policy_name:a\\&b AND name:*", map[string][]string{"name": {"name"}}, "acme")
+			if err != nil {
+				t.Fatalf("PartialSearch() error: %v", err)
+			}
+			if len(rows) != tt.total {
+				t.Fatalf("PartialSearch() returned %d rows, want %d", len(rows), tt.total)
+			}
+			for i, row := range rows {
+				if row["name"] != fmt.Sprintf("node%d", i) {
+					t.Fatalf("row %d = %v", i, row)
+				}
+			}
+			if len(srv.queries) != tt.requests {
+				t.Errorf("made %d requests, want %d", len(srv.queries), tt.requests)
+			}
+			for i := range srv.queries {
+				if srv.queries[i] != "policy_name:a\\&b AND name:*" {
+					t.Errorf("query sent as %q", srv.queries[i])
+				}
+				var fields map[string][]string
+				if err := json.Unmarshal([]byte(srv.bodies[i]), &fields); err != nil || strings.Join(fields["name"], ".") != "name" {
+					t.Errorf("body sent as %q", srv.bodies[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPartialSearchPageErrorStaysOffStdout(t *testing.T) {
+	api := newTestAPI(t, &searchServer{total: partialSearchRows + 1, failAt: partialSearchRows})
+
+	// stdout carries the MCP stdio transport, so nothing may be printed there
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	_, searchErr := api.PartialSearch("node", "*:*", map[string][]string{"name": {"name"}}, "acme")
+	os.Stdout = stdout
+	w.Close()
+	printed, _ := io.ReadAll(r)
+
+	if searchErr == nil || !strings.Contains(searchErr.Error(), "500") {
+		t.Errorf("PartialSearch() error = %v, want the failed page's error", searchErr)
+	}
+	if len(printed) > 0 {
+		t.Errorf("PartialSearch() wrote to stdout: %q", printed)
+	}
+}
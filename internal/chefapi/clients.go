@@ -0,0 +1,124 @@
+package chefapi
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/go-chef/chef"
+
+	"github.com/aknarts/chef-server-mcp/internal/journal"
+)
+
+// ListClients returns a sorted slice of API client names from the specified organization
+func (api *ChefAPI) ListClients(organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	clientsMap, err := client.Clients.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clientsMap))
+	for name := range clientsMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListValidatorClients returns the set of validator client names, using the client search index
+// so a single request covers the whole organization
+func (api *ChefAPI) ListValidatorClients(organization string) (map[string]bool, error) {
+	res, err := api.Search("client", "validator:true", organization)
+	if err != nil {
+		return nil, err
+	}
+	validators := make(map[string]bool, len(res.Rows))
+	for _, row := range res.Rows {
+		if m, ok := row.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				validators[name] = true
+			}
+		}
+	}
+	return validators, nil
+}
+
+// GetClient fetches a single API client from the specified organization
+func (api *ChefAPI) GetClient(name, organization string) (*chef.ApiClient, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.Clients.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// DeleteClient removes an API client from the specified organization
+func (api *ChefAPI) DeleteClient(change Change, name, organization string) error {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return err
+	}
+	if err := api.snapshot(change, journal.ActionDelete, KindClient, name, organization, func() (interface{}, error) {
+		return api.GetClient(name, organization)
+	}); err != nil {
+		return err
+	}
+
+	return client.Clients.Delete(name)
+}
+
+// ListClientKeys returns metadata for every key registered to an API client
+func (api *ChefAPI) ListClientKeys(name, organization string) ([]KeyInfo, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := client.Clients.ListKeys(name)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]KeyInfo, 0, len(items))
+	for _, item := range items {
+		key, err := client.Clients.GetKey(name, item.Name)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, newKeyInfo(item, key))
+	}
+	return keys, nil
+}
+
+// NodeExists reports whether a node with the given name exists in the specified organization
+func (api *ChefAPI) NodeExists(name, organization string) (bool, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := client.Nodes.Get(name); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// IsNotFound reports whether err is a Chef API 404 response
+func IsNotFound(err error) bool {
+	var resp *chef.ErrorResponse
+	if errors.As(err, &resp) && resp.Response != nil {
+		return resp.StatusCode() == http.StatusNotFound
+	}
+	return false
+}
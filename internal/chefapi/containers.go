@@ -0,0 +1,40 @@
+package chefapi
+
+import (
+	"sort"
+
+	"github.com/go-chef/chef"
+)
+
+// ListContainers returns a sorted slice of container names from the specified organization
+func (api *ChefAPI) ListContainers(organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	containersMap, err := client.Containers.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(containersMap))
+	for name := range containersMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetContainer fetches a single container from the specified organization
+func (api *ChefAPI) GetContainer(name, organization string) (*chef.Container, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.Containers.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
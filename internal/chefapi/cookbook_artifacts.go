@@ -0,0 +1,107 @@
+package chefapi
+
+import (
+	"sort"
+
+	"github.com/go-chef/chef"
+)
+
+// ListCookbookArtifacts returns cookbook artifact names and their identifiers from the specified organization.
+// When name is non-empty only that cookbook's artifacts are returned.
+func (api *ChefAPI) ListCookbookArtifacts(name, organization string) (map[string][]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts chef.CBAGetResponse
+	if name == "" {
+		artifacts, err = client.CookbookArtifacts.List()
+	} else {
+		artifacts, err = client.CookbookArtifacts.Get(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string, len(artifacts))
+	for cb, a := range artifacts {
+		ids := make([]string, 0, len(a.CBAVersions))
+		for _, v := range a.CBAVersions {
+			ids = append(ids, v.Identifier)
+		}
+		sort.Strings(ids)
+		out[cb] = ids
+	}
+	return out, nil
+}
+
+// GetCookbookArtifact fetches a cookbook artifact by name and identifier, converted to the
+// chef.Cookbook manifest shape used for versioned cookbooks
+func (api *ChefAPI) GetCookbookArtifact(name, identifier, organization string) (*chef.Cookbook, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	cba, err := client.CookbookArtifacts.GetVersion(name, identifier)
+	if err != nil {
+		return nil, err
+	}
+	return cookbookFromArtifact(cba), nil
+}
+
+// cookbookFromArtifact maps a cookbook artifact onto chef.Cookbook so both can be analysed the same way
+func cookbookFromArtifact(cba chef.CBADetail) *chef.Cookbook {
+	m := cba.Metadata
+	meta := chef.CookbookMeta{
+		Name:            m.Name,
+		Version:         m.Version,
+		Description:     m.Description,
+		LongDescription: m.LongDescription,
+		Maintainer:      m.Maintainer,
+		MaintainerEmail: m.MaintainerEmail,
+		License:         m.License,
+		Platforms:       toInterfaceMap(m.Platforms),
+		Depends:         m.Depends,
+		Reccomends:      m.Reccomends,
+		Suggests:        m.Suggests,
+		Conflicts:       m.Conflicts,
+		Provides:        toInterfaceMap(m.Provides),
+		Replaces:        m.Replaces,
+		Attributes:      m.Attributes,
+		Groupings:       m.Groupings,
+		Recipes:         m.Recipes,
+		SourceUrl:       m.SourceURL,
+		IssueUrl:        m.IssuesURL,
+		Gems:            m.Gems,
+		Privacy:         m.Privacy,
+	}
+	return &chef.Cookbook{
+		CookbookName: cba.Name,
+		Name:         cba.Name,
+		Version:      cba.Version,
+		ChefType:     cba.ChefType,
+		Frozen:       cba.Frozen,
+		Files:        cba.Files,
+		Templates:    cba.Templates,
+		Attributes:   cba.Attributes,
+		Recipes:      cba.Recipes,
+		Definitions:  cba.Definitions,
+		Libraries:    cba.Libraries,
+		Providers:    cba.Providers,
+		Resources:    cba.Resources,
+		RootFiles:    cba.RootFiles,
+		Metadata:     meta,
+	}
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package chefapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeChef is an in-memory Chef server for one organization. Objects are stored by their path
+// below /organizations/acme/, e.g. "nodes/web1" or "data/secrets/db".
+type fakeChef struct {
+	mu       sync.Mutex
+	objects  map[string]json.RawMessage
+	requests []string
+}
+
+func (f *fakeChef) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/organizations/acme/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	f.requests = append(f.requests, r.Method+" "+path)
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet, http.MethodDelete:
+		object, ok := f.objects[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":["not found"]}`))
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(f.objects, path)
+		}
+		_, _ = w.Write(object)
+	case http.MethodPut:
+		if _, ok := f.objects[path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":["not found"]}`))
+			return
+		}
+		f.objects[path] = body
+		_, _ = w.Write(body)
+	case http.MethodPost:
+		var object struct{ Name, ID string }
+		if err := json.Unmarshal(body, &object); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		key := path + "/" + object.Name + object.ID
+		if _, ok := f.objects[key]; ok {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":["already exists"]}`))
+			return
+		}
+		f.objects[key] = body
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"uri":"/organizations/acme/` + key + `"}`))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// field decodes one top-level field of a stored object
+func (f *fakeChef) field(t *testing.T, path, name string) interface{} {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	object, ok := f.objects[path]
+	if !ok {
+		t.Fatalf("%s does not exist", path)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(object, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m[name]
+}
+
+func (f *fakeChef) set(path, object string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[path] = json.RawMessage(object)
+}
+
+func (f *fakeChef) remove(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.objects, path)
+}
+
+func (f *fakeChef) takeRequests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := f.requests
+	f.requests = nil
+	return out
+}
+
+var testChange = Change{Tool: "test", Caller: "test"}
+
+// newFakeAPI starts a fake Chef server holding objects and returns a write-mode client for it
+func newFakeAPI(t *testing.T, objects map[string]string) (*ChefAPI, *fakeChef) {
+	t.Helper()
+	fake := &fakeChef{objects: map[string]json.RawMessage{}}
+	for path, object := range objects {
+		fake.objects[path] = json.RawMessage(object)
+	}
+	return newTestAPI(t, fake), fake
+}
@@ -0,0 +1,74 @@
+package chefapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrReadOnly is returned for any mutating request made while the API is read-only
+var ErrReadOnly = errors.New("Chef server access is read-only (set CHEF_MCP_MODE=write to allow changes)")
+
+// readOnlyTransport refuses every request that could modify Chef server state
+type readOnlyTransport struct {
+	next     http.RoundTripper
+	basePath string // Escaped path of the Chef server base URL, with a trailing slash
+}
+
+func (t readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReadRequest(req, t.basePath) {
+		return nil, fmt.Errorf("%w: refused %s %s", ErrReadOnly, req.Method, req.URL.Path)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// isReadRequest reports whether a request is free of side effects. Only GET is allowed, plus the
+// two POST endpoints Chef uses for queries, which must be the whole path below the server base path:
+//
+//	organizations/<org>/search/<index>                       (partial search)
+//	organizations/<org>/environments/<env>/cookbook_versions (depsolver)
+func isReadRequest(req *http.Request, basePath string) bool {
+	switch req.Method {
+	case http.MethodGet:
+		return true
+	case http.MethodPost:
+		path := req.URL.EscapedPath()
+		if !strings.HasPrefix(path, basePath) {
+			return false
+		}
+		segs := strings.Split(strings.TrimPrefix(path, basePath), "/")
+		return matchSegments(segs, "organizations", "*", "search", "*") ||
+			matchSegments(segs, "organizations", "*", "environments", "*", "cookbook_versions")
+	}
+	return false
+}
+
+// matchSegments reports whether the path segments match pattern exactly, where "*" matches
+// any one non-empty segment
+func matchSegments(segs []string, pattern ...string) bool {
+	if len(segs) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if segs[i] == "" || (p != "*" && p != segs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// roundTripper returns the transport wrapper enforcing the API's access mode
+func (api *ChefAPI) roundTripper() func(http.RoundTripper) http.RoundTripper {
+	if !api.ReadOnly {
+		return nil
+	}
+	basePath := "/"
+	if u, err := url.Parse(api.BaseURL); err == nil && u.EscapedPath() != "" {
+		basePath = ensureTrailingSlash(u.EscapedPath())
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return readOnlyTransport{next: next, basePath: basePath}
+	}
+}
@@ -0,0 +1,77 @@
+package chefapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// okTransport answers every request that gets past the guard
+type okTransport struct{}
+
+func (okTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestReadOnlyTransport(t *testing.T) {
+	tests := []struct {
+		method, url string
+		allowed     bool
+	}{
+		{http.MethodGet, "https://chef.example.com/organizations/acme/nodes/web1", true},
+		{http.MethodGet, "https://chef.example.com/users/alice", true},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/search/node?q=*:*", true},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/environments/prod/cookbook_versions", true},
+
+		{http.MethodPut, "https://chef.example.com/organizations/acme/nodes/web1", false},
+		{http.MethodPut, "https://chef.example.com/organizations/acme/search/node", false},
+		{http.MethodDelete, "https://chef.example.com/organizations/acme/nodes/web1", false},
+		{http.MethodDelete, "https://chef.example.com/organizations/acme/environments/prod/cookbook_versions", false},
+		{http.MethodPatch, "https://chef.example.com/organizations/acme/nodes/web1", false},
+		{http.MethodHead, "https://chef.example.com/organizations/acme/nodes/web1", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/nodes", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/data/search", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/data/search/items", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/data/bag/search/x", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/roles/cookbook_versions", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/environments/prod/cookbook_versions/", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/environments/prod/cookbook_versions/extra", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/search", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/search/node/extra", false},
+		{http.MethodPost, "https://chef.example.com/organizations//search/node", false},
+		{http.MethodPost, "https://chef.example.com/organizations/acme/environments/a%2Fb/cookbook_versions", true},
+		{http.MethodPost, "https://chef.example.com/evil/organizations/acme/search/node", false},
+		{http.MethodPost, "https://chef.example.com/users", false},
+	}
+
+	api := &ChefAPI{BaseURL: "https://chef.example.com/", ReadOnly: true}
+	rt := api.roundTripper()(okTransport{})
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.url, nil)
+		_, err := rt.RoundTrip(req)
+		if tt.allowed && err != nil {
+			t.Errorf("%s %s refused: %v", tt.method, tt.url, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s %s: error = %v, want ErrReadOnly", tt.method, tt.url, err)
+		}
+	}
+}
+
+func TestReadOnlyTransportBasePath(t *testing.T) {
+	api := &ChefAPI{BaseURL: "https://chef.example.com/chef/", ReadOnly: true}
+	rt := api.roundTripper()(okTransport{})
+	if _, err := rt.RoundTrip(httptest.NewRequest(http.MethodPost, "https://chef.example.com/chef/organizations/acme/search/node", nil)); err != nil {
+		t.Errorf("search below the base path refused: %v", err)
+	}
+	if _, err := rt.RoundTrip(httptest.NewRequest(http.MethodPost, "https://chef.example.com/organizations/acme/search/node", nil)); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("search outside the base path: error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestWriteModeHasNoGuard(t *testing.T) {
+	if (&ChefAPI{BaseURL: "https://chef.example.com/"}).roundTripper() != nil {
+		t.Error("write mode should not wrap the transport")
+	}
+}
@@ -0,0 +1,150 @@
+package chefapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-chef/chef"
+
+	"github.com/aknarts/chef-server-mcp/internal/journal"
+)
+
+// Change identifies what is modifying the server; it is recorded with every journal snapshot
+type Change struct {
+	Tool   string
+	Caller string
+}
+
+// Kinds of objects recorded in the journal; RestoreObject supports the first four
+const (
+	KindNode        = "node"
+	KindRole        = "role"
+	KindEnvironment = "environment"
+	KindDataBagItem = "dataBagItem"
+	KindDataBag     = "dataBag"
+	KindClient      = "client"
+)
+
+// snapshot records the current JSON of an object in the journal before it is modified. get returns
+// the object or a not-found error when it does not exist yet. A failed snapshot aborts the change.
+func (api *ChefAPI) snapshot(change Change, action, kind, name, organization string, get func() (interface{}, error)) error {
+	if api.Journal == nil {
+		return nil
+	}
+	entry := journal.Entry{
+		Tool:         change.Tool,
+		Caller:       change.Caller,
+		User:         api.Name,
+		Organization: organization,
+		Kind:         kind,
+		Name:         name,
+		Action:       action,
+	}
+	current, err := get()
+	switch {
+	case err == nil:
+		data, err := json.Marshal(current)
+		if err != nil {
+			return err
+		}
+		entry.Existed = true
+		entry.Object = data
+	case !IsNotFound(err):
+		return fmt.Errorf("snapshot %s '%s' before %s: %w", kind, name, action, err)
+	}
+	if _, err := api.Journal.Record(entry); err != nil {
+		return fmt.Errorf("journal %s '%s' before %s: %w", kind, name, action, err)
+	}
+	return nil
+}
+
+// GetObject fetches the current state of a journaled object; data bag items are named "bag/item"
+func (api *ChefAPI) GetObject(kind, name, organization string) (interface{}, error) {
+	switch kind {
+	case KindNode:
+		return api.GetNode(name, organization)
+	case KindRole:
+		return api.GetRole(name, organization)
+	case KindEnvironment:
+		return api.GetEnvironment(name, organization)
+	case KindDataBagItem:
+		bag, item, err := splitItemName(name)
+		if err != nil {
+			return nil, err
+		}
+		return api.GetDataBagItem(bag, item, organization)
+	}
+	return nil, fmt.Errorf("unsupported object kind '%s'", kind)
+}
+
+// RestoreObject writes a journaled snapshot back, updating the object or re-creating it if it was deleted
+func (api *ChefAPI) RestoreObject(change Change, kind, name string, object json.RawMessage, organization string) error {
+	current, err := api.GetObject(kind, name, organization)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	switch kind {
+	case KindNode:
+		var node chef.Node
+		if err := json.Unmarshal(object, &node); err != nil {
+			return err
+		}
+		if exists {
+			// Automatic attributes belong to chef-client; keep the current ones rather than stale values
+			node.AutomaticAttributes = current.(*chef.Node).AutomaticAttributes
+			_, err = api.UpdateNode(change, node, organization)
+			return err
+		}
+		return api.CreateNode(change, node, organization)
+	case KindRole:
+		var role chef.Role
+		if err := json.Unmarshal(object, &role); err != nil {
+			return err
+		}
+		if exists {
+			_, err = api.UpdateRole(change, &role, organization)
+			return err
+		}
+		return api.CreateRole(change, &role, organization)
+	case KindEnvironment:
+		var env chef.Environment
+		if err := json.Unmarshal(object, &env); err != nil {
+			return err
+		}
+		if exists {
+			_, err = api.UpdateEnvironment(change, &env, organization)
+			return err
+		}
+		return api.CreateEnvironment(change, &env, organization)
+	case KindDataBagItem:
+		var item map[string]interface{}
+		if err := json.Unmarshal(object, &item); err != nil {
+			return err
+		}
+		bag, itemName, err := splitItemName(name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return api.UpdateDataBagItem(change, bag, itemName, item, organization)
+		}
+		return api.CreateDataBagItem(change, bag, item, organization)
+	}
+	return fmt.Errorf("reverting %s objects is not supported", kind)
+}
+
+// ItemName joins a data bag and item into the name used in the journal
+func ItemName(bag, item string) string {
+	return bag + "/" + item
+}
+
+func splitItemName(name string) (string, string, error) {
+	bag, item, ok := strings.Cut(name, "/")
+	if !ok {
+		return "", "", fmt.Errorf("data bag item name '%s' must be bag/item", name)
+	}
+	return bag, item, nil
+}
@@ -0,0 +1,201 @@
+package chefapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-chef/chef"
+
+	"github.com/aknarts/chef-server-mcp/internal/journal"
+)
+
+// newJournaledAPI starts a fake Chef server holding objects and returns a write-mode client that
+// journals into a temporary directory
+func newJournaledAPI(t *testing.T, objects map[string]string) (*ChefAPI, *fakeChef) {
+	t.Helper()
+	api, fake := newFakeAPI(t, objects)
+	var err error
+	if api.Journal, err = journal.New(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	return api, fake
+}
+
+// latestEntry returns the newest journal entry for an object, including its snapshot
+func latestEntry(t *testing.T, api *ChefAPI, kind, name string) journal.Entry {
+	t.Helper()
+	entries, err := api.Journal.List(journal.Filter{Organization: "acme", Kind: kind, Name: name, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatalf("no journal entry for %s '%s'", kind, name)
+	}
+	e, err := api.Journal.Get("acme", entries[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// restoreCase describes one journaled object: where the fake server keeps it, the field the change
+// modifies and the journaled update that modifies it
+type restoreCase struct {
+	kind, name string
+	path       string
+	collection string
+	original   string
+	field      string
+	update     func(api *ChefAPI) error
+}
+
+var restoreCases = []restoreCase{
+	{
+		kind: KindNode, name: "web1", path: "nodes/web1", collection: "nodes",
+		original: `{"name":"web1","chef_environment":"prod","run_list":["recipe[base]"],"normal":{"port":80},"automatic":{"ohai_time":1}}`,
+		field:    "normal",
+		update: func(api *ChefAPI) error {
+			_, err := api.UpdateNode(testChange, chef.Node{Name: "web1", Environment: "prod", NormalAttributes: map[string]interface{}{"port": 8080}}, "acme")
+			return err
+		},
+	},
+	{
+		kind: KindRole, name: "web", path: "roles/web", collection: "roles",
+		original: `{"name":"web","run_list":["recipe[nginx]"],"default_attributes":{"workers":4}}`,
+		field:    "default_attributes",
+		update: func(api *ChefAPI) error {
+			_, err := api.UpdateRole(testChange, &chef.Role{Name: "web", RunList: chef.RunList{"recipe[nginx]"}, DefaultAttributes: map[string]interface{}{"workers": 8}}, "acme")
+			return err
+		},
+	},
+	{
+		kind: KindEnvironment, name: "prod", path: "environments/prod", collection: "environments",
+		original: `{"name":"prod","cookbook_versions":{"nginx":"= 1.0.0"}}`,
+		field:    "cookbook_versions",
+		update: func(api *ChefAPI) error {
+			_, err := api.UpdateEnvironment(testChange, &chef.Environment{Name: "prod", CookbookVersions: map[string]string{"nginx": "= 2.0.0"}}, "acme")
+			return err
+		},
+	},
+	{
+		kind: KindDataBagItem, name: "secrets/db", path: "data/secrets/db", collection: "data/secrets",
+		original: `{"id":"db","user":"app"}`,
+		field:    "user",
+		update: func(api *ChefAPI) error {
+			return api.UpdateDataBagItem(testChange, "secrets", "db", map[string]interface{}{"id": "db", "user": "root"}, "acme")
+		},
+	},
+}
+
+func (c restoreCase) originalField(t *testing.T) interface{} {
+	t.Helper()
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(c.original), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m[c.field]
+}
+
+func TestRestoreAfterUpdate(t *testing.T) {
+	for _, c := range restoreCases {
+		t.Run(c.kind, func(t *testing.T) {
+			api, fake := newJournaledAPI(t, map[string]string{c.path: c.original})
+			if err := c.update(api); err != nil {
+				t.Fatalf("update: %v", err)
+			}
+			if reflect.DeepEqual(fake.field(t, c.path, c.field), c.originalField(t)) {
+				t.Fatal("update did not change the object")
+			}
+
+			entry := latestEntry(t, api, c.kind, c.name)
+			if entry.Action != journal.ActionUpdate || !entry.Existed {
+				t.Fatalf("journal entry = %+v, want an update of an existing object", entry)
+			}
+			fake.takeRequests()
+			if err := api.RestoreObject(testChange, entry.Kind, entry.Name, entry.Object, "acme"); err != nil {
+				t.Fatalf("RestoreObject() error: %v", err)
+			}
+
+			if got := fake.field(t, c.path, c.field); !reflect.DeepEqual(got, c.originalField(t)) {
+				t.Errorf("%s after restore = %v, want %v", c.field, got, c.originalField(t))
+			}
+			if requests := fake.takeRequests(); requests[len(requests)-1] != "PUT "+c.path {
+				t.Errorf("restore requests = %v, want it to end with PUT %s", requests, c.path)
+			}
+			// The restore is itself journaled so it can be reverted
+			if revert := latestEntry(t, api, c.kind, c.name); revert.ID == entry.ID || revert.Action != journal.ActionUpdate {
+				t.Errorf("restore was not journaled as an update: %+v", revert)
+			}
+		})
+	}
+}
+
+func TestRestoreAfterDelete(t *testing.T) {
+	for _, c := range restoreCases {
+		t.Run(c.kind, func(t *testing.T) {
+			api, fake := newJournaledAPI(t, map[string]string{c.path: c.original})
+			// Nodes are deleted through the API; other objects disappear after a journaled update
+			if c.kind == KindNode {
+				if err := api.DeleteNode(testChange, c.name, "acme"); err != nil {
+					t.Fatalf("DeleteNode() error: %v", err)
+				}
+			} else {
+				if err := c.update(api); err != nil {
+					t.Fatalf("update: %v", err)
+				}
+				fake.remove(c.path)
+			}
+
+			entry := latestEntry(t, api, c.kind, c.name)
+			if !entry.Existed {
+				t.Fatalf("journal entry has no snapshot: %+v", entry)
+			}
+			fake.takeRequests()
+			if err := api.RestoreObject(testChange, entry.Kind, entry.Name, entry.Object, "acme"); err != nil {
+				t.Fatalf("RestoreObject() error: %v", err)
+			}
+
+			if got := fake.field(t, c.path, c.field); !reflect.DeepEqual(got, c.originalField(t)) {
+				t.Errorf("%s after restore = %v, want %v", c.field, got, c.originalField(t))
+			}
+			if requests := fake.takeRequests(); requests[len(requests)-1] != "POST "+c.collection {
+				t.Errorf("restore requests = %v, want it to end with POST %s", requests, c.collection)
+			}
+			// Re-creating goes through the same journaled create as every other write
+			revert := latestEntry(t, api, c.kind, c.name)
+			if revert.ID == entry.ID || revert.Action != journal.ActionCreate || revert.Existed {
+				t.Errorf("restore was not journaled as a create of a missing object: %+v", revert)
+			}
+		})
+	}
+}
+
+func TestRestoreNodeKeepsAutomaticAttributes(t *testing.T) {
+	c := restoreCases[0]
+	api, fake := newJournaledAPI(t, map[string]string{c.path: c.original})
+	if err := c.update(api); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	entry := latestEntry(t, api, c.kind, c.name)
+
+	// chef-client ran in between and reported fresh automatic attributes
+	fake.set(c.path, `{"name":"web1","chef_environment":"prod","normal":{"port":8080},"automatic":{"ohai_time":2}}`)
+
+	if err := api.RestoreObject(testChange, entry.Kind, entry.Name, entry.Object, "acme"); err != nil {
+		t.Fatalf("RestoreObject() error: %v", err)
+	}
+	if got := fake.field(t, c.path, "automatic"); !reflect.DeepEqual(got, map[string]interface{}{"ohai_time": float64(2)}) {
+		t.Errorf("automatic after restore = %v, want the current attributes", got)
+	}
+	if got := fake.field(t, c.path, "normal"); !reflect.DeepEqual(got, map[string]interface{}{"port": float64(80)}) {
+		t.Errorf("normal after restore = %v, want the journaled attributes", got)
+	}
+}
+
+func TestRestoreUnsupportedKind(t *testing.T) {
+	api, _ := newJournaledAPI(t, nil)
+	if err := api.RestoreObject(testChange, KindClient, "web1", json.RawMessage(`{}`), "acme"); err == nil {
+		t.Error("expected an error for an unsupported kind")
+	}
+}
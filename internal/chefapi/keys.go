@@ -0,0 +1,134 @@
+package chefapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/go-chef/chef"
+)
+
+// KeyInfo describes a Chef actor key without exposing the key material itself
+type KeyInfo struct {
+	Name           string `json:"name"`
+	ExpirationDate string `json:"expirationDate"`
+	Expired        bool   `json:"expired"`
+	Algorithm      string `json:"algorithm,omitempty"`
+	Bits           int    `json:"bits,omitempty"`
+	Fingerprint    string `json:"fingerprint,omitempty"`
+}
+
+// Key expiration states reported by KeyInfo.Status
+const (
+	KeyExpired      = "expired"
+	KeyExpiring     = "expiring"
+	KeyValid        = "valid"
+	KeyNeverExpires = "never_expires"
+)
+
+// ExpiresAt parses the key expiration date. infinite is true for keys with an "infinity" expiration;
+// a missing or malformed date is an error rather than a key that never expires.
+func (k KeyInfo) ExpiresAt() (t time.Time, infinite bool, err error) {
+	if k.ExpirationDate == "infinity" {
+		return time.Time{}, true, nil
+	}
+	if k.ExpirationDate == "" {
+		return time.Time{}, false, fmt.Errorf("key '%s' has no expiration date", k.Name)
+	}
+	t, err = time.Parse(time.RFC3339, k.ExpirationDate)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("parse expiration date '%s' of key '%s': %w", k.ExpirationDate, k.Name, err)
+	}
+	return t, false, nil
+}
+
+// Status classifies the key relative to now: expired, expiring within the window, valid, or never_expires
+func (k KeyInfo) Status(now time.Time, window time.Duration) (string, error) {
+	t, infinite, err := k.ExpiresAt()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case infinite:
+		return KeyNeverExpires, nil
+	case k.Expired || !t.After(now):
+		return KeyExpired, nil
+	case t.Before(now.Add(window)):
+		return KeyExpiring, nil
+	}
+	return KeyValid, nil
+}
+
+// ListUserKeys returns metadata for every key registered to a user
+func (api *ChefAPI) ListUserKeys(name string) ([]KeyInfo, error) {
+	client, err := api.getServerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := client.Users.ListKeys(name)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]KeyInfo, 0, len(items))
+	for _, item := range items {
+		key, err := client.Users.GetKey(name, item.Name)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, newKeyInfo(item, key))
+	}
+	return keys, nil
+}
+
+// DecryptWithKey decrypts data encrypted (RSA PKCS#1 v1.5) with the public half of the configured
+// user's key, as chef-vault does for shared secrets
+func (api *ChefAPI) DecryptWithKey(ciphertext []byte) ([]byte, error) {
+	pk, err := chef.PrivateKeyFromString([]byte(api.KeyMaterial))
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	return rsa.DecryptPKCS1v15(nil, pk, ciphertext)
+}
+
+// newKeyInfo combines a key listing entry with the key details returned by the keys endpoint
+func newKeyInfo(item chef.KeyItem, key chef.AccessKey) KeyInfo {
+	info := KeyInfo{
+		Name:           item.Name,
+		ExpirationDate: key.ExpirationDate,
+		Expired:        item.Expired,
+	}
+	info.Algorithm, info.Bits, info.Fingerprint = publicKeyMetadata(key.PublicKey)
+	return info
+}
+
+// publicKeyMetadata parses a PEM public key and returns its algorithm, size and SHA256 fingerprint
+func publicKeyMetadata(pemData string) (algorithm string, bits int, fingerprint string) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return "", 0, ""
+	}
+	sum := sha256.Sum256(block.Bytes)
+	fingerprint = "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		// Older Chef servers return PKCS#1 "RSA PUBLIC KEY" blocks
+		if rsaPub, rerr := x509.ParsePKCS1PublicKey(block.Bytes); rerr == nil {
+			return "RSA", rsaPub.N.BitLen(), fingerprint
+		}
+		return "", 0, fingerprint
+	}
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return "RSA", k.N.BitLen(), fingerprint
+	case *ecdsa.PublicKey:
+		return "ECDSA", k.Params().BitSize, fingerprint
+	}
+	return "", 0, fingerprint
+}
@@ -0,0 +1,37 @@
+package chefapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyInfoStatus(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+	tests := []struct {
+		name    string
+		key     KeyInfo
+		want    string
+		wantErr bool
+	}{
+		{"never expires", KeyInfo{Name: "default", ExpirationDate: "infinity"}, KeyNeverExpires, false},
+		{"expired", KeyInfo{Name: "old", ExpirationDate: "2025-12-31T00:00:00Z"}, KeyExpired, false},
+		{"flagged expired by server", KeyInfo{Name: "flagged", ExpirationDate: "2027-01-01T00:00:00Z", Expired: true}, KeyExpired, false},
+		{"expiring", KeyInfo{Name: "soon", ExpirationDate: "2026-01-15T00:00:00Z"}, KeyExpiring, false},
+		{"valid", KeyInfo{Name: "later", ExpirationDate: "2026-06-01T00:00:00Z"}, KeyValid, false},
+		{"missing date", KeyInfo{Name: "blank"}, "", true},
+		{"malformed date", KeyInfo{Name: "bad", ExpirationDate: "next tuesday"}, "", true},
+		{"infinity is case sensitive", KeyInfo{Name: "caps", ExpirationDate: "Infinity"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.key.Status(now, window)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Status() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Status() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package chefapi
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/go-chef/chef"
+)
+
+// ListAccessibleOrganizations returns the names of organizations the configured user can access.
+// It queries the server-level /organizations endpoint and falls back to /users/{name}/organizations
+// when the user is not a server admin.
+func (api *ChefAPI) ListAccessibleOrganizations() ([]string, error) {
+	client, err := api.getServerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	orgs, err := client.Organizations.List()
+	if err == nil {
+		names := make([]string, 0, len(orgs))
+		for name := range orgs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	if !IsAuthError(err) {
+		return nil, err
+	}
+
+	userOrgs, err := api.ListUserOrganizations(api.Name)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(userOrgs))
+	for _, org := range userOrgs {
+		names = append(names, org.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListOrganizations returns details for every organization the configured user can access.
+// Server admins get the full /organizations listing; other users fall back to their own memberships.
+func (api *ChefAPI) ListOrganizations() ([]chef.Organization, error) {
+	client, err := api.getServerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	orgList, err := client.Organizations.List()
+	if err != nil {
+		if !IsAuthError(err) {
+			return nil, err
+		}
+		orgs, err := api.ListUserOrganizations(api.Name)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(orgs, func(i, j int) bool { return orgs[i].Name < orgs[j].Name })
+		return orgs, nil
+	}
+
+	orgs := make([]chef.Organization, 0, len(orgList))
+	for name := range orgList {
+		org, err := client.Organizations.Get(name)
+		if err != nil {
+			// Keep the entry even if details cannot be read
+			org = chef.Organization{Name: name}
+		}
+		orgs = append(orgs, org)
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].Name < orgs[j].Name })
+	return orgs, nil
+}
+
+// ListUserOrganizations returns the organizations the named user belongs to
+func (api *ChefAPI) ListUserOrganizations(user string) ([]chef.Organization, error) {
+	client, err := api.getServerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Response shape: [{"organization": {"name": "...", "full_name": "...", "guid": "..."}}]
+	var entries []struct {
+		Organization chef.Organization `json:"organization"`
+	}
+	if err := getJSON(client, "users/"+url.PathEscape(user)+"/organizations", &entries); err != nil {
+		return nil, err
+	}
+	orgs := make([]chef.Organization, 0, len(entries))
+	for _, e := range entries {
+		orgs = append(orgs, e.Organization)
+	}
+	return orgs, nil
+}
+
+// IsAuthError reports whether err is a Chef API 401/403 response
+func IsAuthError(err error) bool {
+	var resp *chef.ErrorResponse
+	if errors.As(err, &resp) && resp.Response != nil {
+		return resp.StatusCode() == http.StatusForbidden || resp.StatusCode() == http.StatusUnauthorized
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package chefapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-chef/chef"
+)
+
+// PolicyGroupPins maps policy names to the revision id pinned in a policy group
+type PolicyGroupPins map[string]string
+
+// ListPolicyGroups returns every policy group with the policy revisions it pins
+func (api *ChefAPI) ListPolicyGroups(organization string) (map[string]PolicyGroupPins, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := client.PolicyGroups.List()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]PolicyGroupPins, len(groups))
+	for name, g := range groups {
+		out[name] = policyGroupPins(g)
+	}
+	return out, nil
+}
+
+// GetPolicyGroup returns the policy revisions pinned by a single policy group
+func (api *ChefAPI) GetPolicyGroup(name, organization string) (PolicyGroupPins, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := client.PolicyGroups.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return policyGroupPins(g), nil
+}
+
+// ListPolicies returns a sorted slice of policy names from the specified organization
+func (api *ChefAPI) ListPolicies(organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := client.Policies.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListPolicyRevisions returns the sorted revision ids stored for a policy
+func (api *ChefAPI) ListPolicyRevisions(name, organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := client.Policies.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]string, 0, len(policy["revisions"]))
+	for rev := range policy["revisions"] {
+		revisions = append(revisions, rev)
+	}
+	sort.Strings(revisions)
+	return revisions, nil
+}
+
+// GetPolicyRevision fetches the full lock of a policy revision
+func (api *ChefAPI) GetPolicyRevision(name, revision, organization string) (*chef.RevisionDetailsResponse, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := client.Policies.GetRevisionDetails(name, revision)
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// GetPolicyGroupPolicy fetches the lock of the policy revision currently pinned in a policy group
+func (api *ChefAPI) GetPolicyGroupPolicy(group, name, organization string) (*chef.RevisionDetailsResponse, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := client.PolicyGroups.GetPolicy(group, name)
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// ListNodesByPolicy returns the sorted names of nodes matching a policy group and/or policy name
+func (api *ChefAPI) ListNodesByPolicy(policyGroup, policyName, organization string) ([]string, error) {
+	var query string
+	switch {
+	case policyGroup != "" && policyName != "":
+		query = fmt.Sprintf("policy_group:%s AND policy_name:%s", escapeQueryTerm(policyGroup), escapeQueryTerm(policyName))
+	case policyGroup != "":
+		query = fmt.Sprintf("policy_group:%s", escapeQueryTerm(policyGroup))
+	case policyName != "":
+		query = fmt.Sprintf("policy_name:%s", escapeQueryTerm(policyName))
+	default:
+		return nil, fmt.Errorf("policy group or policy name must be specified")
+	}
+
+	rows, err := api.PartialSearch("node", query, map[string][]string{"name": {"name"}}, organization)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if name, ok := row["name"].(string); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// policyGroupPins flattens the go-chef policy group representation into policy -> revision id
+func policyGroupPins(g chef.PolicyGroup) PolicyGroupPins {
+	pins := make(PolicyGroupPins, len(g.Policies))
+	for policy, rev := range g.Policies {
+		pins[policy] = rev["revision_id"]
+	}
+	return pins
+}
+
+// escapeQueryTerm backslash-escapes Lucene special characters and whitespace so a value
+// is matched literally as a single search term
+func escapeQueryTerm(term string) string {
+	var b strings.Builder
+	for _, r := range term {
+		if strings.ContainsRune(`+-&|!(){}[]^"~*?:\/`, r) || unicode.IsSpace(r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,23 @@
+package chefapi
+
+import "testing"
+
+func TestEscapeQueryTerm(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"prod", "prod"},
+		{"base_policy", "base_policy"},
+		{"web-app", `web\-app`},
+		{"a:b", `a\:b`},
+		{"x OR policy_name:*", `x\ OR\ policy_name\:\*`},
+		{`(a)[b]{c}"d"`, `\(a\)\[b\]\{c\}\"d\"`},
+		{`back\slash/path`, `back\\slash\/path`},
+		{"a&&b||!c^~?+", `a\&\&b\|\|\!c\^\~\?\+`},
+	}
+	for _, tt := range tests {
+		if got := escapeQueryTerm(tt.in); got != tt.want {
+			t.Errorf("escapeQueryTerm(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package chefapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-chef/chef"
+)
+
+// RunListProblem describes a run-list entry that cannot be used
+type RunListProblem struct {
+	Item    string `json:"item"`
+	Problem string `json:"problem"`
+}
+
+// NormalizeRunList parses run-list entries into their canonical recipe[...] / role[...] form
+func NormalizeRunList(items []string) ([]string, error) {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		rli, err := chef.NewRunListItem(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rli.String())
+	}
+	return out, nil
+}
+
+// ValidateRunList checks that every role and recipe referenced by the run list exists
+func (api *ChefAPI) ValidateRunList(items []string, organization string) ([]RunListProblem, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []RunListProblem{}
+	var recipes map[string]bool
+	versions := make(map[string]map[string]bool)
+	for _, item := range items {
+		rli, err := chef.NewRunListItem(item)
+		if err != nil {
+			problems = append(problems, RunListProblem{Item: item, Problem: err.Error()})
+			continue
+		}
+
+		if rli.IsRole() {
+			if _, err := client.Roles.Get(rli.Name); err != nil {
+				if !IsNotFound(err) {
+					return nil, err
+				}
+				problems = append(problems, RunListProblem{Item: item, Problem: fmt.Sprintf("role '%s' does not exist", rli.Name)})
+			}
+			continue
+		}
+
+		cookbook := strings.SplitN(rli.Name, "::", 2)[0]
+		if rli.Version != "" {
+			// Pinned recipes only need the cookbook version to exist
+			if _, ok := versions[cookbook]; !ok {
+				versions[cookbook], err = cookbookVersions(client, cookbook)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !versions[cookbook][rli.Version] {
+				problems = append(problems, RunListProblem{Item: item, Problem: fmt.Sprintf("cookbook '%s' has no version %s", cookbook, rli.Version)})
+			}
+			continue
+		}
+
+		if recipes == nil {
+			all, err := client.Cookbooks.ListAllRecipes()
+			if err != nil {
+				return nil, err
+			}
+			recipes = make(map[string]bool, len(all))
+			for _, r := range all {
+				recipes[recipeName(r)] = true
+			}
+		}
+		if !recipes[recipeName(rli.Name)] {
+			problems = append(problems, RunListProblem{Item: item, Problem: fmt.Sprintf("recipe '%s' not found in the latest cookbook versions", rli.Name)})
+		}
+	}
+	return problems, nil
+}
+
+// ExpandRunList resolves roles, honoring their per-environment run lists, into the ordered
+// and de-duplicated list of recipes chef-client would run
+func (api *ChefAPI) ExpandRunList(items []string, environment, organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &runListExpander{client: client, environment: environment, roles: make(map[string]bool), seen: make(map[string]bool), versions: make(map[string]string)}
+	if err := e.expand(items); err != nil {
+		return nil, err
+	}
+	return e.recipes, nil
+}
+
+// ExpandRunListVersioned expands the run list like ExpandRunList but keeps the version a recipe is
+// pinned to, in the "name@version" form chef-client sends to the depsolver (e.g. "nginx::server@1.2.3")
+func (api *ChefAPI) ExpandRunListVersioned(items []string, environment, organization string) ([]string, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &runListExpander{client: client, environment: environment, roles: make(map[string]bool), seen: make(map[string]bool), versions: make(map[string]string)}
+	if err := e.expand(items); err != nil {
+		return nil, err
+	}
+	recipes := make([]string, 0, len(e.recipes))
+	for _, name := range e.recipes {
+		if v := e.versions[name]; v != "" {
+			name += "@" + v
+		}
+		recipes = append(recipes, name)
+	}
+	return recipes, nil
+}
+
+type runListExpander struct {
+	client      *chef.Client
+	environment string
+	roles       map[string]bool // Roles already expanded; repeats are skipped like chef-client does
+	seen        map[string]bool
+	versions    map[string]string // Version pins of recipes given as recipe[name@version]
+	recipes     []string
+}
+
+func (e *runListExpander) expand(items []string) error {
+	for _, item := range items {
+		rli, err := chef.NewRunListItem(item)
+		if err != nil {
+			return err
+		}
+		if rli.IsRecipe() {
+			name := recipeName(rli.Name)
+			if !e.seen[name] {
+				e.seen[name] = true
+				e.recipes = append(e.recipes, name)
+			}
+			if rli.Version != "" {
+				if v, ok := e.versions[name]; ok && v != rli.Version {
+					return fmt.Errorf("recipe '%s' is pinned to both %s and %s", name, v, rli.Version)
+				}
+				e.versions[name] = rli.Version
+			}
+			continue
+		}
+
+		if e.roles[rli.Name] {
+			continue
+		}
+		e.roles[rli.Name] = true
+		role, err := e.client.Roles.Get(rli.Name)
+		if err != nil {
+			return fmt.Errorf("expand role '%s': %w", rli.Name, err)
+		}
+		runList := []string(role.RunList)
+		if role.EnvRunList != nil {
+			if envList, ok := role.EnvRunList[e.environment]; ok {
+				runList = envList
+			}
+		}
+		if err := e.expand(runList); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recipeName strips the implicit "::default" suffix so "nginx" and "nginx::default" compare equal
+func recipeName(name string) string {
+	return strings.TrimSuffix(name, "::default")
+}
+
+// cookbookVersions returns the set of versions uploaded for a cookbook
+func cookbookVersions(client *chef.Client, name string) (map[string]bool, error) {
+	found := make(map[string]bool)
+	result, err := client.Cookbooks.GetAvailableVersions(name, "all")
+	if err != nil {
+		if IsNotFound(err) {
+			return found, nil
+		}
+		return nil, err
+	}
+	for _, v := range result[name].Versions {
+		found[v.Version] = true
+	}
+	return found, nil
+}
+
+// RunListSample is a distinct run list used by nodes in an environment
+type RunListSample struct {
+	RunList []string `json:"runList"`
+	Nodes   int      `json:"nodes"`
+}
+
+// EnvironmentRunLists returns up to limit distinct run lists of the nodes in an environment,
+// most common first
+func (api *ChefAPI) EnvironmentRunLists(environment string, limit int, organization string) ([]RunListSample, error) {
+	rows, err := api.PartialSearch("node", "chef_environment:"+escapeQueryTerm(environment), map[string][]string{"run_list": {"run_list"}}, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	var samples []RunListSample
+	for _, row := range rows {
+		list, _ := row["run_list"].([]interface{})
+		runList := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				runList = append(runList, s)
+			}
+		}
+		key := strings.Join(runList, ",")
+		if i, ok := index[key]; ok {
+			samples[i].Nodes++
+			continue
+		}
+		index[key] = len(samples)
+		samples = append(samples, RunListSample{RunList: runList, Nodes: 1})
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Nodes > samples[j].Nodes })
+	if limit > 0 && len(samples) > limit {
+		samples = samples[:limit]
+	}
+	return samples, nil
+}
@@ -0,0 +1,35 @@
+package chefapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestEnvironmentRunLists(t *testing.T) {
+	var query string
+	api := newTestAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		rows := []map[string]interface{}{}
+		for _, runList := range [][]string{{"role[web]"}, {"role[db]"}, {"role[web]"}, {"role[web]", "recipe[x]"}, {"role[db]"}, {"role[web]"}} {
+			rows = append(rows, map[string]interface{}{"data": map[string]interface{}{"run_list": runList}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"total": len(rows), "start": 0, "rows": rows})
+	}))
+
+	samples, err := api.EnvironmentRunLists("prod OR chef_environment:*", 2, "acme")
+	if err != nil {
+		t.Fatalf("EnvironmentRunLists() error: %v", err)
+	}
+	if want := `chef_environment:prod\ OR\ chef_environment\:\*`; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	want := []RunListSample{
+		{RunList: []string{"role[web]"}, Nodes: 3},
+		{RunList: []string{"role[db]"}, Nodes: 2},
+	}
+	if !reflect.DeepEqual(samples, want) {
+		t.Errorf("EnvironmentRunLists() = %+v, want %+v", samples, want)
+	}
+}
@@ -0,0 +1,47 @@
+package chefapi
+
+import (
+	"time"
+
+	"github.com/go-chef/chef"
+)
+
+// ServerAPIVersion is the response of the /server_api_version endpoint
+type ServerAPIVersion struct {
+	MinAPIVersion int `json:"min_api_version"`
+	MaxAPIVersion int `json:"max_api_version"`
+}
+
+// GetServerStatus calls /_status and returns the per-upstream health with the measured round-trip time
+func (api *ChefAPI) GetServerStatus() (chef.Status, time.Duration, error) {
+	client, err := api.getServerClient()
+	if err != nil {
+		return chef.Status{}, 0, err
+	}
+
+	start := time.Now()
+	status, err := client.Status.Get()
+	return status, time.Since(start), err
+}
+
+// GetLicense returns node count and license limit information from /license
+func (api *ChefAPI) GetLicense() (chef.License, error) {
+	client, err := api.getServerClient()
+	if err != nil {
+		return chef.License{}, err
+	}
+
+	return client.License.Get()
+}
+
+// GetServerAPIVersion returns the API version range supported by the Chef server
+func (api *ChefAPI) GetServerAPIVersion() (ServerAPIVersion, error) {
+	client, err := api.getServerClient()
+	if err != nil {
+		return ServerAPIVersion{}, err
+	}
+
+	var v ServerAPIVersion
+	err = getJSON(client, "server_api_version", &v)
+	return v, err
+}
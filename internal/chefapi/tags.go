@@ -0,0 +1,102 @@
+package chefapi
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/go-chef/chef"
+)
+
+// TagCount is the number of nodes carrying a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// NodeTags returns the tags stored in a node's normal attributes
+func NodeTags(node *chef.Node) []string {
+	var tags []string
+	list, _ := node.NormalAttributes["tags"].([]interface{})
+	for _, t := range list {
+		if s, ok := t.(string); ok {
+			tags = append(tags, s)
+		}
+	}
+	return tags
+}
+
+// SetNodeTags replaces the tags in a node's normal attributes
+func SetNodeTags(node *chef.Node, tags []string) {
+	if node.NormalAttributes == nil {
+		node.NormalAttributes = make(map[string]interface{})
+	}
+	list := make([]interface{}, len(tags))
+	for i, t := range tags {
+		list[i] = t
+	}
+	node.NormalAttributes["tags"] = list
+}
+
+// ModifyNode reads a node, applies mutate to a copy and saves the copy when mutate reports a
+// change. Chef has no conditional writes, so this is a best-effort read-modify-write: mutate should
+// check the fields it depends on against the fresh read, but a chef-client run that saves the node
+// between the read and the write is still overwritten. The node as read is returned with the result.
+func (api *ChefAPI) ModifyNode(change Change, name, organization string, mutate func(*chef.Node) (bool, error)) (before, after *chef.Node, err error) {
+	original, err := api.GetNode(name, organization)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Mutate a deep copy so the node as read stays untouched
+	snapshot, err := json.Marshal(original)
+	if err != nil {
+		return nil, nil, err
+	}
+	var updated chef.Node
+	if err := json.Unmarshal(snapshot, &updated); err != nil {
+		return nil, nil, err
+	}
+	changed, err := mutate(&updated)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !changed {
+		return original, original, nil
+	}
+
+	saved, err := api.UpdateNode(change, updated, organization)
+	if err != nil {
+		return nil, nil, err
+	}
+	return original, saved, nil
+}
+
+// CountTags counts tags across the nodes matching a search query
+func (api *ChefAPI) CountTags(query, organization string) ([]TagCount, int, error) {
+	rows, err := api.PartialSearch("node", query, map[string][]string{"tags": {"tags"}}, organization)
+	if err != nil {
+		return nil, 0, err
+	}
+	counts := make(map[string]int)
+	for _, row := range rows {
+		list, _ := row["tags"].([]interface{})
+		seen := make(map[string]bool, len(list))
+		for _, t := range list {
+			if s, ok := t.(string); ok && !seen[s] {
+				seen[s] = true
+				counts[s]++
+			}
+		}
+	}
+	tags := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, len(rows), nil
+}
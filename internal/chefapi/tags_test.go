@@ -0,0 +1,94 @@
+package chefapi
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chef/chef"
+)
+
+const taggedNode = `{"name":"web1","chef_environment":"prod","normal":{"tags":["web"],"port":80},"automatic":{"ohai_time":1}}`
+
+func TestNodeTags(t *testing.T) {
+	node := &chef.Node{}
+	if tags := NodeTags(node); len(tags) != 0 {
+		t.Errorf("NodeTags() of an untagged node = %v", tags)
+	}
+	SetNodeTags(node, []string{"web", "canary"})
+	if got := NodeTags(node); !reflect.DeepEqual(got, []string{"web", "canary"}) {
+		t.Errorf("NodeTags() = %v", got)
+	}
+
+	node.NormalAttributes["tags"] = []interface{}{"web", 3, "db"}
+	if got := NodeTags(node); !reflect.DeepEqual(got, []string{"web", "db"}) {
+		t.Errorf("NodeTags() with a non-string tag = %v", got)
+	}
+}
+
+func TestModifyNode(t *testing.T) {
+	api, fake := newFakeAPI(t, map[string]string{"nodes/web1": taggedNode})
+	before, after, err := api.ModifyNode(testChange, "web1", "acme", func(node *chef.Node) (bool, error) {
+		SetNodeTags(node, append(NodeTags(node), "canary"))
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyNode() error: %v", err)
+	}
+	if got := NodeTags(before); !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("before tags = %v, want the node as read", got)
+	}
+	if got := NodeTags(after); !reflect.DeepEqual(got, []string{"web", "canary"}) {
+		t.Errorf("after tags = %v", got)
+	}
+	if got := fake.takeRequests(); strings.Join(got, ",") != "GET nodes/web1,PUT nodes/web1" {
+		t.Errorf("requests = %v, want one read and one write", got)
+	}
+
+	// The whole node is written back, so attributes the mutation did not touch are kept
+	if got := fake.field(t, "nodes/web1", "normal"); !reflect.DeepEqual(got, map[string]interface{}{"tags": []interface{}{"web", "canary"}, "port": float64(80)}) {
+		t.Errorf("normal after write = %v", got)
+	}
+	if got := fake.field(t, "nodes/web1", "automatic"); !reflect.DeepEqual(got, map[string]interface{}{"ohai_time": float64(1)}) {
+		t.Errorf("automatic after write = %v", got)
+	}
+}
+
+func TestModifyNodeWithoutChange(t *testing.T) {
+	errStale := errors.New("stale")
+	tests := []struct {
+		name    string
+		mutate  func(*chef.Node) (bool, error)
+		wantErr error
+	}{
+		{"nothing to change", func(*chef.Node) (bool, error) { return false, nil }, nil},
+		{"mutation refused", func(*chef.Node) (bool, error) { return true, errStale }, errStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, fake := newFakeAPI(t, map[string]string{"nodes/web1": taggedNode})
+			before, after, err := api.ModifyNode(testChange, "web1", "acme", tt.mutate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ModifyNode() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && before != after {
+				t.Error("an unchanged node should be returned as both before and after")
+			}
+			if got := fake.takeRequests(); strings.Join(got, ",") != "GET nodes/web1" {
+				t.Errorf("requests = %v, want only the read", got)
+			}
+		})
+	}
+}
+
+func TestModifyNodeMissing(t *testing.T) {
+	api, _ := newFakeAPI(t, nil)
+	_, _, err := api.ModifyNode(testChange, "web1", "acme", func(*chef.Node) (bool, error) {
+		t.Error("mutate called for a missing node")
+		return false, nil
+	})
+	if !IsNotFound(err) {
+		t.Errorf("ModifyNode() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,13 @@
+package chefapi
+
+import "github.com/go-chef/chef"
+
+// GetUniverse returns every cookbook version in the specified organization with its dependencies
+func (api *ChefAPI) GetUniverse(organization string) (chef.Universe, error) {
+	client, err := api.getClientForOrg(organization)
+	if err != nil {
+		return chef.Universe{}, err
+	}
+
+	return client.Universe.Get()
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -11,9 +12,47 @@ import (
 type Config struct {
 	ChefUser      string
 	ChefKeyPath   string
-	ChefServerURL string            // Base Chef server URL without organization
-	DefaultOrg    string            // Default organization to use if none specified
-	OrgAliases    map[string]string // Organization aliases mapping
+	ChefServerURL string              // Base Chef server URL without organization
+	DefaultOrg    string              // Default organization to use if none specified
+	Mode          string              // Access mode: ModeReadOnly (default) or ModeWrite
+	ArchiveDir    string              // Directory for node archives written before decommissioning
+	JournalDir    string              // Directory of the snapshots taken before every change
+	OrgAliases    map[string]string   // Organization aliases mapping
+	OrgGroups     map[string][]string // Named groups of organizations for multi-org tools
+	DiffIgnore    []string            // Attribute paths skipped when diffing nodes
+
+	DataBagSecretFile string            // Default secret for encrypted data bags
+	DataBagSecrets    map[string]string // Per-bag secret file overrides
+	DecryptBags       []string          // Data bags allowed to be decrypted ("*" allows all)
+	AllowPlaintext    bool              // Permit callers to request unredacted decrypted values
+	DataBagSchemas    map[string]string // Per-bag JSON Schema files validated before writes
+
+	RedactDisabled  bool     // Turn off secret redaction of tool output
+	RedactKeys      []string // Key name patterns to redact (nil selects the defaults)
+	RedactPaths     []string // Attribute paths to redact
+	RedactDetectors []string // Value detectors to enable (nil selects all)
+}
+
+// Access modes selected by CHEF_MCP_MODE
+const (
+	ModeReadOnly = "readonly"
+	ModeWrite    = "write"
+)
+
+// DefaultDiffIgnore lists volatile automatic attributes that change on every chef-client run
+var DefaultDiffIgnore = []string{
+	"ohai_time",
+	"uptime",
+	"uptime_seconds",
+	"idletime",
+	"idletime_seconds",
+	"memory.free",
+	"memory.available",
+	"memory.cached",
+	"memory.buffers",
+	"memory.swap.free",
+	"memory.swap.cached",
+	"cpu.*.mhz",
 }
 
 func LoadFromEnv() *Config {
@@ -22,7 +61,21 @@ func LoadFromEnv() *Config {
 		ChefKeyPath:   os.Getenv("CHEF_KEY_PATH"),
 		ChefServerURL: os.Getenv("CHEF_SERVER_URL"),
 		DefaultOrg:    os.Getenv("CHEF_DEFAULT_ORG"),
+		Mode:          ModeReadOnly,
+		ArchiveDir:    os.Getenv("CHEF_ARCHIVE_DIR"),
+		JournalDir:    os.Getenv("CHEF_JOURNAL_DIR"),
 		OrgAliases:    make(map[string]string),
+		OrgGroups:     make(map[string][]string),
+		DiffIgnore:    DefaultDiffIgnore,
+
+		DataBagSecretFile: os.Getenv("CHEF_DATA_BAG_SECRET_FILE"),
+		DataBagSecrets:    make(map[string]string),
+		DataBagSchemas:    make(map[string]string),
+		DecryptBags:       splitList(os.Getenv("CHEF_DECRYPT_DATA_BAGS")),
+		AllowPlaintext:    os.Getenv("CHEF_ALLOW_PLAINTEXT_SECRETS") == "true",
+
+		RedactDisabled: os.Getenv("CHEF_REDACT_DISABLE") == "true",
+		RedactPaths:    splitList(os.Getenv("CHEF_REDACT_PATHS")),
 	}
 
 	// Backward compatibility: if CHEF_SERVER_URL includes "/organizations/<org>",
@@ -54,9 +107,92 @@ func LoadFromEnv() *Config {
 		}
 	}
 
+	// Load organization groups from environment variable (JSON format)
+	if groupsJSON := os.Getenv("CHEF_ORG_GROUPS"); groupsJSON != "" {
+		if err := json.Unmarshal([]byte(groupsJSON), &cfg.OrgGroups); err != nil {
+			// If JSON parsing fails, try simple group=org1|org2 format
+			cfg.OrgGroups = parseSimpleGroups(groupsJSON)
+		}
+	}
+
+	if mode := strings.TrimSpace(os.Getenv("CHEF_MCP_MODE")); mode != "" {
+		cfg.Mode = strings.ToLower(mode)
+	}
+
+	// Keep the change journal in the user cache directory unless configured otherwise
+	if cfg.JournalDir == "" {
+		if cache, err := os.UserCacheDir(); err == nil {
+			cfg.JournalDir = filepath.Join(cache, "chef-server-mcp", "journal")
+		}
+	}
+
+	// Override volatile diff paths with a comma separated list, e.g. "ohai_time,memory.free"
+	if ignore := os.Getenv("CHEF_DIFF_IGNORE_PATHS"); ignore != "" {
+		cfg.DiffIgnore = splitList(ignore)
+	}
+
+	// Load per-bag secret files from environment variable (JSON format)
+	if secretsJSON := os.Getenv("CHEF_DATA_BAG_SECRETS"); secretsJSON != "" {
+		if err := json.Unmarshal([]byte(secretsJSON), &cfg.DataBagSecrets); err != nil {
+			// If JSON parsing fails, try simple bag=path format
+			cfg.DataBagSecrets = parseSimpleAliases(secretsJSON)
+		}
+	}
+
+	// Load per-bag schema files from environment variable (JSON format)
+	if schemasJSON := os.Getenv("CHEF_DATA_BAG_SCHEMAS"); schemasJSON != "" {
+		if err := json.Unmarshal([]byte(schemasJSON), &cfg.DataBagSchemas); err != nil {
+			// If JSON parsing fails, try simple bag=path format
+			cfg.DataBagSchemas = parseSimpleAliases(schemasJSON)
+		}
+	}
+
+	// Key name patterns are regular expressions separated by commas; detectors are named, e.g. "pem,jwt".
+	// Setting either variable to an empty string disables that part of the redaction.
+	if keys, ok := os.LookupEnv("CHEF_REDACT_KEYS"); ok {
+		cfg.RedactKeys = append([]string{}, splitList(keys)...)
+	}
+	if detectors, ok := os.LookupEnv("CHEF_REDACT_DETECTORS"); ok {
+		cfg.RedactDetectors = append([]string{}, splitList(detectors)...)
+	}
+
 	return cfg
 }
 
+// WriteEnabled reports whether tools may modify the Chef server
+func (c *Config) WriteEnabled() bool {
+	return c.Mode == ModeWrite
+}
+
+// DecryptAllowed reports whether items of the data bag may be decrypted
+func (c *Config) DecryptAllowed(bag string) bool {
+	for _, b := range c.DecryptBags {
+		if b == "*" || b == bag {
+			return true
+		}
+	}
+	return false
+}
+
+// DataBagSecretPath returns the secret file for a data bag, preferring a per-bag mapping
+func (c *Config) DataBagSecretPath(bag string) string {
+	if path, ok := c.DataBagSecrets[bag]; ok {
+		return path
+	}
+	return c.DataBagSecretFile
+}
+
+// splitList splits a comma separated list, trimming whitespace and dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 // parseSimpleAliases parses aliases in format "alias1=org1,alias2=org2"
 func parseSimpleAliases(aliasStr string) map[string]string {
 	aliases := make(map[string]string)
@@ -69,6 +205,46 @@ func parseSimpleAliases(aliasStr string) map[string]string {
 	return aliases
 }
 
+// parseSimpleGroups parses groups in format "group1=org1|org2,group2=org3"
+func parseSimpleGroups(groupStr string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, pair := range strings.Split(groupStr, ",") {
+		if kv := strings.SplitN(strings.TrimSpace(pair), "=", 2); len(kv) == 2 {
+			var orgs []string
+			for _, org := range strings.Split(kv[1], "|") {
+				if org = strings.TrimSpace(org); org != "" {
+					orgs = append(orgs, org)
+				}
+			}
+			groups[strings.TrimSpace(kv[0])] = orgs
+		}
+	}
+	return groups
+}
+
+// ResolveOrganizations expands a list of organization names, aliases and group names into
+// a de-duplicated list of actual organization names, preserving input order
+func (c *Config) ResolveOrganizations(inputs []string) []string {
+	seen := make(map[string]bool)
+	var orgs []string
+	add := func(org string) {
+		if org != "" && !seen[org] {
+			seen[org] = true
+			orgs = append(orgs, org)
+		}
+	}
+	for _, in := range inputs {
+		if members, ok := c.OrgGroups[in]; ok {
+			for _, m := range members {
+				add(c.ResolveOrganization(m))
+			}
+			continue
+		}
+		add(c.ResolveOrganization(in))
+	}
+	return orgs
+}
+
 // ResolveOrganization resolves an organization name or alias to the actual organization name
 func (c *Config) ResolveOrganization(orgInput string) string {
 	if orgInput == "" {
@@ -0,0 +1,88 @@
+package confirm
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long an issued confirmation stays valid
+const DefaultTTL = 5 * time.Minute
+
+// Errors returned by Redeem
+var (
+	ErrUnknown = errors.New("confirmation is unknown, expired or already used")
+	ErrChanged = errors.New("objects changed since the preview was made; preview again")
+)
+
+// Store issues short-lived, single-use confirmations bound to a digest of what was previewed
+type Store struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	pending map[string]entry
+}
+
+type entry struct {
+	binding string
+	expires time.Time
+}
+
+// NewStore creates a Store whose confirmations expire after ttl
+func NewStore(ttl time.Duration) *Store {
+	return &Store{ttl: ttl, pending: make(map[string]entry)}
+}
+
+// Issue returns a new confirmation bound to binding and the time it expires
+func (s *Store) Issue(binding string) (string, time.Time, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	id := hex.EncodeToString(b)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.pending {
+		if now.After(e.expires) {
+			delete(s.pending, k)
+		}
+	}
+	s.pending[id] = entry{binding: binding, expires: now.Add(s.ttl)}
+	return id, now.Add(s.ttl), nil
+}
+
+// Redeem consumes a confirmation. It fails with ErrUnknown when the confirmation was never issued,
+// expired or was already used, and with ErrChanged when binding no longer matches.
+func (s *Store) Redeem(id, binding string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.pending[id]
+	if !ok {
+		return ErrUnknown
+	}
+	delete(s.pending, id)
+	if time.Now().After(e.expires) {
+		return ErrUnknown
+	}
+	if e.binding != binding {
+		return ErrChanged
+	}
+	return nil
+}
+
+// Hash returns a stable digest of JSON-encodable values for use as a binding
+func Hash(values ...interface{}) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
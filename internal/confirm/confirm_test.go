@@ -0,0 +1,145 @@
+package confirm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedeem(t *testing.T) {
+	s := NewStore(DefaultTTL)
+	id, expires, err := s.Issue("plan-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(id) != 32 {
+		t.Errorf("confirmation %q is not 16 hex encoded bytes", id)
+	}
+	if d := time.Until(expires); d <= 0 || d > DefaultTTL {
+		t.Errorf("expires in %v, want within %v", d, DefaultTTL)
+	}
+
+	if err := s.Redeem(id, "plan-a"); err != nil {
+		t.Fatalf("Redeem() error: %v", err)
+	}
+	if err := s.Redeem(id, "plan-a"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("second Redeem() error = %v, want ErrUnknown", err)
+	}
+}
+
+func TestRedeemUnknown(t *testing.T) {
+	s := NewStore(DefaultTTL)
+	if err := s.Redeem("never-issued", "plan"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Redeem() error = %v, want ErrUnknown", err)
+	}
+}
+
+func TestRedeemChanged(t *testing.T) {
+	s := NewStore(DefaultTTL)
+	id, _, err := s.Issue("plan-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Redeem(id, "plan-b"); !errors.Is(err, ErrChanged) {
+		t.Fatalf("Redeem() error = %v, want ErrChanged", err)
+	}
+	// A mismatched attempt still uses the confirmation up
+	if err := s.Redeem(id, "plan-a"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Redeem() after mismatch error = %v, want ErrUnknown", err)
+	}
+}
+
+func TestRedeemExpired(t *testing.T) {
+	s := NewStore(time.Millisecond)
+	id, _, err := s.Issue("plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := s.Redeem(id, "plan"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Redeem() error = %v, want ErrUnknown", err)
+	}
+}
+
+func TestIssuePrunesExpired(t *testing.T) {
+	s := NewStore(time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if _, _, err := s.Issue("plan"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, _, err := s.Issue("plan"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(s.pending); n != 1 {
+		t.Errorf("%d pending confirmations, want 1", n)
+	}
+}
+
+func TestIssueUniqueIDs(t *testing.T) {
+	s := NewStore(DefaultTTL)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id, _, err := s.Issue("plan")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("confirmation %s issued twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestHash(t *testing.T) {
+	type plan struct {
+		Node  string            `json:"node"`
+		After []string          `json:"after"`
+		Attrs map[string]string `json:"attrs"`
+	}
+	a := plan{Node: "web1", After: []string{"role[web]"}, Attrs: map[string]string{"x": "1", "y": "2"}}
+	b := plan{Node: "web1", After: []string{"role[web]"}, Attrs: map[string]string{"y": "2", "x": "1"}}
+
+	ha, err := Hash("updateNodeRunList", a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		hb, err := Hash("updateNodeRunList", b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ha != hb {
+			t.Fatalf("Hash() not stable: %s != %s", ha, hb)
+		}
+	}
+
+	// The digest covers each value's JSON encoding in turn
+	sum := sha256.Sum256([]byte("\"updateNodeRunList\"\n" + `{"node":"web1","after":["role[web]"],"attrs":{"x":"1","y":"2"}}` + "\n"))
+	if want := hex.EncodeToString(sum[:]); ha != want {
+		t.Errorf("Hash() = %s, want %s", ha, want)
+	}
+
+	changed := a
+	changed.After = []string{"role[db]"}
+	for _, other := range [][]interface{}{
+		{"updateNodeRunList", changed},
+		{"addNodeTags", a},
+		{"updateNodeRunList"},
+	} {
+		h, err := Hash(other...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h == ha {
+			t.Errorf("Hash(%v) collides with the original plan", other)
+		}
+	}
+
+	if _, err := Hash(func() {}); err == nil {
+		t.Error("expected an error for a value that cannot be encoded")
+	}
+}
@@ -0,0 +1,141 @@
+package constraint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a Chef cookbook version (major.minor[.patch])
+type Version [3]int
+
+// ParseVersion parses "1.2" or "1.2.3"; missing components default to zero
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return v, fmt.Errorf("invalid version '%s': expected x.y or x.y.z", s)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version '%s': component '%s' is not a non-negative integer", s, p)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater than o
+func (v Version) Compare(o Version) int {
+	for i := range v {
+		switch {
+		case v[i] < o[i]:
+			return -1
+		case v[i] > o[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// CompareStrings orders two version strings numerically. Unparseable versions sort
+// after valid ones and are compared as plain strings among themselves.
+func CompareStrings(a, b string) int {
+	va, errA := ParseVersion(a)
+	vb, errB := ParseVersion(b)
+	switch {
+	case errA == nil && errB == nil:
+		return va.Compare(vb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// Constraint is a single Chef version constraint such as "~> 1.2" or ">= 2.0.0"
+type Constraint struct {
+	Op      string
+	Version Version
+	parts   int // number of version components given, needed for ~>
+}
+
+var operators = []string{">=", "<=", "~>", "=", ">", "<"}
+
+// Parse parses a Chef version constraint. A bare version means "= version" and an
+// empty constraint matches any version.
+func Parse(s string) (Constraint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Constraint{Op: ">=", parts: 3}, nil
+	}
+	op := "="
+	for _, candidate := range operators {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			s = strings.TrimSpace(s[len(candidate):])
+			break
+		}
+	}
+	v, err := ParseVersion(s)
+	if err != nil {
+		return Constraint{}, err
+	}
+	return Constraint{Op: op, Version: v, parts: len(strings.Split(s, "."))}, nil
+}
+
+// Check reports whether version v satisfies the constraint
+func (c Constraint) Check(v Version) bool {
+	cmp := v.Compare(c.Version)
+	switch c.Op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case "~>":
+		if cmp < 0 {
+			return false
+		}
+		// ~> 1.2 allows < 2.0; ~> 1.2.3 allows < 1.3.0
+		var upper Version
+		if c.parts == 2 {
+			upper = Version{c.Version[0] + 1, 0, 0}
+		} else {
+			upper = Version{c.Version[0], c.Version[1] + 1, 0}
+		}
+		return v.Compare(upper) < 0
+	}
+	return false
+}
+
+// Satisfies parses version and constraint strings and reports whether the version satisfies the constraint
+func Satisfies(version, constraint string) (bool, error) {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	c, err := Parse(constraint)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v), nil
+}
+
+func (c Constraint) String() string {
+	if c.parts == 2 {
+		return fmt.Sprintf("%s %d.%d", c.Op, c.Version[0], c.Version[1])
+	}
+	return fmt.Sprintf("%s %s", c.Op, c.Version)
+}
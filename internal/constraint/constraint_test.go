@@ -0,0 +1,115 @@
+package constraint
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Version
+		wantErr bool
+	}{
+		{"1.2.3", Version{1, 2, 3}, false},
+		{"1.2", Version{1, 2, 0}, false},
+		{" 10.0.1 ", Version{10, 0, 1}, false},
+		{"1", Version{}, true},
+		{"1.2.3.4", Version{}, true},
+		{"1.x", Version{}, true},
+		{"1.-2", Version{}, true},
+		{"", Version{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"~> 1.2", "~> 1.2", false},
+		{"~>1.2.3", "~> 1.2.3", false},
+		{">= 2.0.0", ">= 2.0.0", false},
+		{"= 1.0", "= 1.0", false},
+		{"1.0.1", "= 1.0.1", false},
+		{"", ">= 0.0.0", false},
+		{"<= 3.1.4", "<= 3.1.4", false},
+		{"> 1.0", "> 1.0", false},
+		{"< 2.0.0", "< 2.0.0", false},
+		{"~> banana", "", true},
+		{">= 1", "", true},
+	}
+	for _, tt := range tests {
+		c, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && c.String() != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.in, c.String(), tt.want)
+		}
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version, constraint string
+		want                bool
+	}{
+		{"1.2.0", "~> 1.2", true},
+		{"1.9.9", "~> 1.2", true},
+		{"2.0.0", "~> 1.2", false},
+		{"1.1.9", "~> 1.2", false},
+		{"1.2.3", "~> 1.2.3", true},
+		{"1.2.9", "~> 1.2.3", true},
+		{"1.3.0", "~> 1.2.3", false},
+		{"1.2.2", "~> 1.2.3", false},
+		{"2.0.0", ">= 2.0.0", true},
+		{"10.0.0", ">= 9.0.0", true},
+		{"1.9.9", ">= 2.0.0", false},
+		{"1.0.0", "= 1.0", true},
+		{"1.0.1", "= 1.0", false},
+		{"1.0.1", "1.0.1", true},
+		{"0.0.1", "", true},
+		{"2.0.0", "> 1.9", true},
+		{"1.9.0", "> 1.9", false},
+		{"1.9.0", "< 2.0", true},
+		{"2.0.0", "< 2.0", false},
+		{"3.1.4", "<= 3.1.4", true},
+		{"3.1.5", "<= 3.1.4", false},
+	}
+	for _, tt := range tests {
+		got, err := Satisfies(tt.version, tt.constraint)
+		if err != nil {
+			t.Errorf("Satisfies(%q, %q) error: %v", tt.version, tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Satisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+	if _, err := Satisfies("1.0.0", "~> x"); err == nil {
+		t.Error("expected an error for an invalid constraint")
+	}
+}
+
+func TestCompareStrings(t *testing.T) {
+	versions := []string{"10.0.0", "9.0.0", "not-a-version", "1.10.0", "1.9.2", "1.2"}
+	sort.Slice(versions, func(i, j int) bool { return CompareStrings(versions[i], versions[j]) < 0 })
+	want := []string{"1.2", "1.9.2", "1.10.0", "9.0.0", "10.0.0", "not-a-version"}
+	for i := range want {
+		if versions[i] != want[i] {
+			t.Fatalf("sorted = %v, want %v", versions, want)
+		}
+	}
+}
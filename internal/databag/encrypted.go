@@ -0,0 +1,211 @@
+package databag
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RedactedValue replaces decrypted values unless plaintext output was requested and permitted
+const RedactedValue = "[REDACTED]"
+
+// encryptedValue is the on-the-wire form of a single encrypted data bag item field (formats v1-v3)
+type encryptedValue struct {
+	EncryptedData string `json:"encrypted_data"`
+	IV            string `json:"iv"`
+	Version       int    `json:"version"`
+	Cipher        string `json:"cipher"`
+	HMAC          string `json:"hmac,omitempty"`     // v2
+	AuthTag       string `json:"auth_tag,omitempty"` // v3
+}
+
+// LoadSecret reads a data bag secret file, trimming surrounding whitespace the same way chef-client does
+func LoadSecret(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read data bag secret '%s': %w", path, err)
+	}
+	secret := strings.TrimSpace(string(b))
+	if secret == "" {
+		return nil, fmt.Errorf("data bag secret '%s' is empty", path)
+	}
+	return []byte(secret), nil
+}
+
+// IsEncrypted reports whether an item looks like an encrypted data bag item,
+// i.e. every field except "id" is an encrypted value
+func IsEncrypted(item map[string]interface{}) bool {
+	found := false
+	for k, v := range item {
+		if k == "id" {
+			continue
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m["encrypted_data"]; !ok {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// Decrypt returns a copy of item with every encrypted field decrypted using secret.
+// The "id" field is never encrypted and is copied as-is.
+func Decrypt(item map[string]interface{}, secret []byte) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(item))
+	for k, v := range item {
+		if k == "id" {
+			out[k] = v
+			continue
+		}
+		value, err := decryptValue(v, secret)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt field '%s': %w", k, err)
+		}
+		out[k] = value
+	}
+	return out, nil
+}
+
+// Redact replaces every leaf value below the item's top level with RedactedValue, keeping the
+// structure (keys and list lengths) visible. The "id" field is preserved.
+func Redact(item map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(item))
+	for k, v := range item {
+		if k == "id" {
+			out[k] = v
+			continue
+		}
+		out[k] = redactValue(v)
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, child := range t {
+			out[k] = redactValue(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, child := range t {
+			out[i] = redactValue(child)
+		}
+		return out
+	case nil:
+		return nil
+	}
+	return RedactedValue
+}
+
+func decryptValue(raw interface{}, secret []byte) (interface{}, error) {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var ev encryptedValue
+	if err := json.Unmarshal(b, &ev); err != nil || ev.EncryptedData == "" {
+		return nil, errors.New("value is not an encrypted data bag field")
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(ev.EncryptedData)
+	if err != nil {
+		return nil, fmt.Errorf("decode encrypted_data: %w", err)
+	}
+	iv, err := base64.StdEncoding.DecodeString(ev.IV)
+	if err != nil {
+		return nil, fmt.Errorf("decode iv: %w", err)
+	}
+	key := sha256.Sum256(secret)
+
+	var plaintext []byte
+	switch ev.Version {
+	case 1:
+		plaintext, err = decryptCBC(key[:], iv, ciphertext)
+	case 2:
+		// The HMAC covers the base64 encoded ciphertext and is keyed with the raw secret
+		expected, derr := base64.StdEncoding.DecodeString(ev.HMAC)
+		if derr != nil {
+			return nil, fmt.Errorf("decode hmac: %w", derr)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(ev.EncryptedData))
+		if !hmac.Equal(mac.Sum(nil), expected) {
+			return nil, errors.New("hmac mismatch: wrong secret or tampered item")
+		}
+		plaintext, err = decryptCBC(key[:], iv, ciphertext)
+	case 3:
+		tag, derr := base64.StdEncoding.DecodeString(ev.AuthTag)
+		if derr != nil {
+			return nil, fmt.Errorf("decode auth_tag: %w", derr)
+		}
+		plaintext, err = decryptGCM(key[:], iv, ciphertext, tag)
+	default:
+		return nil, fmt.Errorf("unsupported encrypted data bag version %d", ev.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Plaintext is {"json_wrapper": <value>}
+	var wrapper struct {
+		Value interface{} `json:"json_wrapper"`
+	}
+	if err := json.Unmarshal(plaintext, &wrapper); err != nil {
+		return nil, errors.New("decrypted data is not valid JSON: wrong secret?")
+	}
+	return wrapper.Value, nil
+}
+
+func decryptCBC(key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid ciphertext or iv length")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// Strip PKCS#7 padding
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > block.BlockSize() || pad > len(plaintext) {
+		return nil, errors.New("invalid padding: wrong secret?")
+	}
+	for _, b := range plaintext[len(plaintext)-pad:] {
+		if int(b) != pad {
+			return nil, errors.New("invalid padding: wrong secret?")
+		}
+	}
+	return plaintext[:len(plaintext)-pad], nil
+}
+
+func decryptGCM(key, iv, ciphertext, tag []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, iv, append(append([]byte{}, ciphertext...), tag...), nil)
+	if err != nil {
+		return nil, errors.New("authentication failed: wrong secret or tampered item")
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,187 @@
+package databag
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The fixtures under testdata follow the on-disk format written by `knife data bag create --secret`:
+// Ruby Base64.encode64 line wrapping, an HMAC over the encoded ciphertext for v2 and a GCM
+// auth tag for v3.
+
+func loadItem(t *testing.T, name string) map[string]interface{} {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var item map[string]interface{}
+	if err := json.Unmarshal(b, &item); err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func testSecret(t *testing.T) []byte {
+	t.Helper()
+	secret, err := LoadSecret("testdata/secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+func TestDecrypt(t *testing.T) {
+	want := map[string]interface{}{
+		"id":       "db",
+		"password": "hunter2",
+		"config": map[string]interface{}{
+			"user":    "admin",
+			"ports":   []interface{}{float64(5432), float64(5433)},
+			"enabled": true,
+		},
+	}
+	secret := testSecret(t)
+	for _, fixture := range []string{"encrypted_v1.json", "encrypted_v2.json", "encrypted_v3.json"} {
+		t.Run(fixture, func(t *testing.T) {
+			item := loadItem(t, fixture)
+			if !IsEncrypted(item) {
+				t.Fatal("IsEncrypted() = false")
+			}
+			got, err := Decrypt(item, secret)
+			if err != nil {
+				t.Fatalf("Decrypt() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Decrypt() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	secret := testSecret(t)
+	tamper := func(fixture, field, key string, edit func(string) string) map[string]interface{} {
+		item := loadItem(t, fixture)
+		v := item[field].(map[string]interface{})
+		v[key] = edit(v[key].(string))
+		return item
+	}
+	flipFirstChar := func(s string) string {
+		if s[0] == 'A' {
+			return "B" + s[1:]
+		}
+		return "A" + s[1:]
+	}
+
+	tests := []struct {
+		name    string
+		item    map[string]interface{}
+		secret  []byte
+		wantErr string
+	}{
+		{"v1 wrong secret", loadItem(t, "encrypted_v1.json"), []byte("not the secret"), "wrong secret"},
+		{"v2 wrong secret", loadItem(t, "encrypted_v2.json"), []byte("not the secret"), "hmac mismatch"},
+		{"v3 wrong secret", loadItem(t, "encrypted_v3.json"), []byte("not the secret"), "authentication failed"},
+		{"v2 tampered hmac", tamper("encrypted_v2.json", "password", "hmac", flipFirstChar), secret, "hmac mismatch"},
+		{"v2 tampered ciphertext", tamper("encrypted_v2.json", "password", "encrypted_data", flipFirstChar), secret, "hmac mismatch"},
+		{"v3 tampered auth tag", tamper("encrypted_v3.json", "password", "auth_tag", flipFirstChar), secret, "authentication failed"},
+		{"v3 tampered ciphertext", tamper("encrypted_v3.json", "config", "encrypted_data", flipFirstChar), secret, "authentication failed"},
+		{"v1 truncated ciphertext", tamper("encrypted_v1.json", "password", "encrypted_data", func(string) string { return "AAAA" }), secret, "invalid ciphertext"},
+		{"unsupported version", map[string]interface{}{"id": "x", "v": map[string]interface{}{"encrypted_data": "AAAA", "iv": "AAAA", "version": 9}}, secret, "unsupported encrypted data bag version 9"},
+		{"plain field", map[string]interface{}{"id": "x", "v": "plain"}, secret, "not an encrypted data bag field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decrypt(tt.item, tt.secret)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Decrypt() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecryptCBCPadding(t *testing.T) {
+	key := sha256.Sum256([]byte("secret"))
+	iv := make([]byte, aes.BlockSize)
+	encrypt := func(plaintext []byte) []byte {
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, plaintext)
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		plaintext []byte
+		want      string
+		wantErr   bool
+	}{
+		{"one byte of padding", []byte("fifteen bytes!!\x01"), "fifteen bytes!!", false},
+		{"full padding block", append([]byte("sixteen bytes!!!"), []byte(strings.Repeat("\x10", 16))...), "sixteen bytes!!!", false},
+		{"zero padding byte", []byte("fifteen bytes!!\x00"), "", true},
+		{"padding larger than block", []byte("fifteen bytes!!\x11"), "", true},
+		{"inconsistent padding bytes", []byte("fourteen bytes\x01\x02"), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decryptCBC(key[:], iv, encrypt(tt.plaintext))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decryptCBC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("decryptCBC() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := decryptCBC(key[:], iv[:8], encrypt([]byte("fifteen bytes!!\x01"))); err == nil {
+		t.Error("expected an error for a short iv")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	item := map[string]interface{}{
+		"id":   "db",
+		"pass": "hunter2",
+		"nested": map[string]interface{}{
+			"list": []interface{}{"a", float64(1)},
+			"none": nil,
+		},
+	}
+	want := map[string]interface{}{
+		"id":   "db",
+		"pass": RedactedValue,
+		"nested": map[string]interface{}{
+			"list": []interface{}{RedactedValue, RedactedValue},
+			"none": nil,
+		},
+	}
+	if got := Redact(item); !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadSecretTrimsWhitespace(t *testing.T) {
+	secret := testSecret(t)
+	if strings.ContainsAny(string(secret), " \n") {
+		t.Errorf("LoadSecret() kept whitespace: %q", secret)
+	}
+
+	empty := t.TempDir() + "/empty"
+	if err := os.WriteFile(empty, []byte(" \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSecret(empty); err == nil {
+		t.Error("expected an error for a blank secret file")
+	}
+}
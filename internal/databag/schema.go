@@ -0,0 +1,61 @@
+package databag
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// SchemaItem is the data bag item holding the bag's JSON Schema under its "schema" key
+const SchemaItem = "_schema"
+
+// ParseSchema compiles a JSON Schema (draft 2020-12) document
+func ParseSchema(data []byte) (*jsonschema.Resolved, error) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %w", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, fmt.Errorf("resolve JSON schema: %w", err)
+	}
+	return resolved, nil
+}
+
+// LoadSchema reads and compiles a JSON Schema file
+func LoadSchema(path string) (*jsonschema.Resolved, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema file '%s': %w", path, err)
+	}
+	return ParseSchema(data)
+}
+
+// SchemaFromItem compiles the schema stored in a bag's SchemaItem
+func SchemaFromItem(item map[string]interface{}) (*jsonschema.Resolved, error) {
+	schema, ok := item["schema"]
+	if !ok {
+		return nil, fmt.Errorf("data bag item '%s' has no \"schema\" key", SchemaItem)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(data)
+}
+
+// Validate checks an item against a compiled schema
+func Validate(schema *jsonschema.Resolved, item map[string]interface{}) error {
+	// Round-trip so numbers and nested values have the types the validator expects
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	var instance interface{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return err
+	}
+	return schema.Validate(instance)
+}
@@ -0,0 +1,112 @@
+package databag
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// VaultKeysSuffix is appended to a vault item name to form the item holding its keys
+const VaultKeysSuffix = "_keys"
+
+// ErrNoVaultKey is returned when the vault item has no shared secret encrypted for the actor
+var ErrNoVaultKey = errors.New("no vault key for actor")
+
+// VaultMetadata describes who can decrypt a chef-vault item, as recorded in its "<item>_keys" item
+type VaultMetadata struct {
+	Admins      []string `json:"admins"`
+	Clients     []string `json:"clients"`
+	SearchQuery string   `json:"searchQuery,omitempty"`
+	Mode        string   `json:"mode" jsonschema:"default (keys stored in the _keys item) or sparse (one item per actor)"`
+}
+
+// ParseVaultKeys extracts vault metadata from a "<item>_keys" data bag item
+func ParseVaultKeys(keys map[string]interface{}) VaultMetadata {
+	meta := VaultMetadata{
+		Admins:  stringList(keys["admins"]),
+		Clients: stringList(keys["clients"]),
+		Mode:    "default",
+	}
+	switch q := keys["search_query"].(type) {
+	case string:
+		meta.SearchQuery = q
+	case []interface{}:
+		meta.SearchQuery = strings.Join(stringList(q), " ")
+	}
+	if mode, ok := keys["mode"].(string); ok && mode != "" {
+		meta.Mode = mode
+	}
+	return meta
+}
+
+// IsVaultKeys reports whether an item looks like a chef-vault keys item
+func IsVaultKeys(item map[string]interface{}) bool {
+	id, _ := item["id"].(string)
+	_, hasAdmins := item["admins"]
+	return strings.HasSuffix(id, VaultKeysSuffix) && hasAdmins
+}
+
+// EncryptedSharedSecret returns the base64 encoded shared secret encrypted for actor in a default-mode
+// keys item. ok is false when the actor has no entry.
+func EncryptedSharedSecret(keys map[string]interface{}, actor string) (secret string, ok bool) {
+	secret, ok = keys[actor].(string)
+	return secret, ok && secret != ""
+}
+
+// SparseKeyItem returns the name of the per-actor item used by sparse-mode vaults
+func SparseKeyItem(item, actor string) string {
+	return item + VaultKeysSuffix + "_" + actor
+}
+
+// VaultSharedSecret returns the base64 encoded shared secret encrypted for actor. Default-mode vaults
+// keep every actor's entry in the "<item>_keys" item; sparse-mode vaults store it under the actor's
+// name in a separate "<item>_keys_<actor>" item, which is fetched with getItem; getItem returns a
+// nil item when it does not exist. ErrNoVaultKey means the actor has no entry; other errors come
+// from fetching the sparse item.
+func VaultSharedSecret(keys map[string]interface{}, item, actor string, getItem func(name string) (map[string]interface{}, error)) (string, error) {
+	if ParseVaultKeys(keys).Mode == "sparse" {
+		name := SparseKeyItem(item, actor)
+		sparse, err := getItem(name)
+		if err != nil {
+			return "", fmt.Errorf("get sparse vault key '%s': %w", name, err)
+		}
+		if sparse == nil {
+			return "", ErrNoVaultKey
+		}
+		keys = sparse
+	}
+	secret, ok := EncryptedSharedSecret(keys, actor)
+	if !ok {
+		return "", ErrNoVaultKey
+	}
+	return secret, nil
+}
+
+// DecryptVault decrypts a chef-vault item. The shared secret is RSA encrypted to the actor's public key
+// and is recovered with decryptKey, which holds the matching private key.
+func DecryptVault(item map[string]interface{}, encSecret string, decryptKey func(ciphertext []byte) ([]byte, error)) (map[string]interface{}, error) {
+	// chef-vault stores the secret with line-wrapped base64
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encSecret), ""))
+	if err != nil {
+		return nil, fmt.Errorf("decode vault shared secret: %w", err)
+	}
+	secret, err := decryptKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt vault shared secret: %w", err)
+	}
+	return Decrypt(item, secret)
+}
+
+func stringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, e := range list {
+		if s, ok := e.(string); ok {
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
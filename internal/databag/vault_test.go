@@ -0,0 +1,120 @@
+package databag
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// testdata/vault_item.json is encrypted with a random shared secret, which the keys items hold
+// RSA encrypted to testdata/vault_admin.pem for the admin "alice" (and the client "web1").
+
+func vaultDecrypter(t *testing.T) func([]byte) ([]byte, error) {
+	t.Helper()
+	b, err := os.ReadFile("testdata/vault_admin.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		t.Fatal("vault_admin.pem is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func(ciphertext []byte) ([]byte, error) {
+		return rsa.DecryptPKCS1v15(nil, key, ciphertext)
+	}
+}
+
+// itemStore serves sparse key items from testdata by name
+func itemStore(t *testing.T, items map[string]string) func(string) (map[string]interface{}, error) {
+	return func(name string) (map[string]interface{}, error) {
+		fixture, ok := items[name]
+		if !ok {
+			return nil, nil
+		}
+		return loadItem(t, fixture), nil
+	}
+}
+
+func TestDecryptVault(t *testing.T) {
+	want := map[string]interface{}{
+		"id":       "db",
+		"password": "hunter2",
+		"users":    []interface{}{"app", "report"},
+	}
+	sparseItems := map[string]string{"db_keys_alice": "vault_keys_sparse_alice.json"}
+
+	tests := []struct {
+		name  string
+		keys  string
+		mode  string
+		actor string
+	}{
+		{"default mode", "vault_keys_default.json", "default", "alice"},
+		{"sparse mode", "vault_keys_sparse.json", "sparse", "alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := loadItem(t, tt.keys)
+			if !IsVaultKeys(keys) {
+				t.Fatal("IsVaultKeys() = false")
+			}
+			meta := ParseVaultKeys(keys)
+			if meta.Mode != tt.mode || !reflect.DeepEqual(meta.Admins, []string{"alice"}) || meta.SearchQuery != "role:web" {
+				t.Fatalf("ParseVaultKeys() = %+v", meta)
+			}
+
+			encSecret, err := VaultSharedSecret(keys, "db", tt.actor, itemStore(t, sparseItems))
+			if err != nil {
+				t.Fatalf("VaultSharedSecret() error: %v", err)
+			}
+			got, err := DecryptVault(loadItem(t, "vault_item.json"), encSecret, vaultDecrypter(t))
+			if err != nil {
+				t.Fatalf("DecryptVault() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("DecryptVault() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestVaultSharedSecretMissingActor(t *testing.T) {
+	noItems := itemStore(t, nil)
+	for _, fixture := range []string{"vault_keys_default.json", "vault_keys_sparse.json"} {
+		t.Run(fixture, func(t *testing.T) {
+			_, err := VaultSharedSecret(loadItem(t, fixture), "db", "mallory", noItems)
+			if !errors.Is(err, ErrNoVaultKey) {
+				t.Errorf("VaultSharedSecret() error = %v, want ErrNoVaultKey", err)
+			}
+		})
+	}
+}
+
+func TestVaultSharedSecretFetchError(t *testing.T) {
+	forbidden := errors.New("403 Forbidden")
+	failing := func(string) (map[string]interface{}, error) { return nil, forbidden }
+	_, err := VaultSharedSecret(loadItem(t, "vault_keys_sparse.json"), "db", "alice", failing)
+	if !errors.Is(err, forbidden) || errors.Is(err, ErrNoVaultKey) {
+		t.Errorf("VaultSharedSecret() error = %v, want the fetch error and not ErrNoVaultKey", err)
+	}
+}
+
+func TestDecryptVaultWrongKey(t *testing.T) {
+	keys := loadItem(t, "vault_keys_default.json")
+	encSecret, err := VaultSharedSecret(keys, "db", "alice", itemStore(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongKey := func([]byte) ([]byte, error) { return nil, rsa.ErrDecryption }
+	if _, err := DecryptVault(loadItem(t, "vault_item.json"), encSecret, wrongKey); !errors.Is(err, rsa.ErrDecryption) {
+		t.Errorf("DecryptVault() error = %v, want rsa.ErrDecryption", err)
+	}
+}
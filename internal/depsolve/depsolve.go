@@ -0,0 +1,222 @@
+package depsolve
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aknarts/chef-server-mcp/internal/constraint"
+)
+
+// maxSteps bounds the backtracking search so pathological universes fail instead of hanging
+const maxSteps = 100000
+
+// ErrGaveUp is returned when the search exceeds maxSteps without an answer
+var ErrGaveUp = errors.New("depsolver gave up")
+
+// Universe lists every cookbook version with its dependency constraints: cookbook -> version -> dependency -> constraint
+type Universe map[string]map[string]map[string]string
+
+// Solve selects a version of every cookbook reachable from roots so that the environment pins
+// and all dependency constraints hold, preferring the newest versions like the Chef server does.
+// roots and pins map cookbook names to constraints ("" matches any version).
+func Solve(universe Universe, roots, pins map[string]string) (map[string]string, error) {
+	s := &solver{universe: universe, pins: make(map[string]constraint.Constraint, len(pins))}
+	for name, pin := range pins {
+		c, err := constraint.Parse(pin)
+		if err != nil {
+			return nil, fmt.Errorf("environment pin for '%s': %w", name, err)
+		}
+		s.pins[name] = c
+	}
+
+	reqs := make(map[string][]requirement)
+	pending := make([]string, 0, len(roots))
+	for name, spec := range roots {
+		c, err := constraint.Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("run list constraint for '%s': %w", name, err)
+		}
+		reqs[name] = append(reqs[name], requirement{constraint: c, from: "run list"})
+		pending = append(pending, name)
+	}
+	sort.Strings(pending)
+
+	selected, ok := s.solve(map[string]constraint.Version{}, reqs, pending)
+	if s.steps > maxSteps {
+		return nil, fmt.Errorf("%w after %d steps", ErrGaveUp, maxSteps)
+	}
+	if !ok {
+		return nil, errors.New(s.failure)
+	}
+	out := make(map[string]string, len(selected))
+	for name, v := range selected {
+		out[name] = v.String()
+	}
+	return out, nil
+}
+
+// Roots turns an expanded run list ("nginx::server" or "nginx::server@1.2.3") into the cookbook
+// constraints Solve starts from: "" for unpinned cookbooks and "= version" for pinned recipes
+func Roots(recipes []string) (map[string]string, error) {
+	roots := make(map[string]string, len(recipes))
+	for _, recipe := range recipes {
+		name, version, pinned := strings.Cut(recipe, "@")
+		cookbook := strings.SplitN(name, "::", 2)[0]
+		spec := ""
+		if pinned {
+			if _, err := constraint.ParseVersion(version); err != nil {
+				return nil, fmt.Errorf("recipe '%s': %w", recipe, err)
+			}
+			spec = "= " + version
+		}
+		if current, ok := roots[cookbook]; ok && current != "" {
+			if spec != "" && spec != current {
+				return nil, fmt.Errorf("run list pins cookbook '%s' to both %s and %s", cookbook, strings.TrimPrefix(current, "= "), version)
+			}
+			continue
+		}
+		roots[cookbook] = spec
+	}
+	return roots, nil
+}
+
+// requirement is a constraint on a cookbook together with what imposed it, for error messages
+type requirement struct {
+	constraint constraint.Constraint
+	from       string
+}
+
+type solver struct {
+	universe Universe
+	pins     map[string]constraint.Constraint
+	steps    int
+	failure  string // Deepest reason a cookbook could not be resolved
+	depth    int
+}
+
+func (s *solver) solve(selected map[string]constraint.Version, reqs map[string][]requirement, pending []string) (map[string]constraint.Version, bool) {
+	if s.steps++; s.steps > maxSteps {
+		return nil, false
+	}
+	// Skip cookbooks already chosen; their constraints were checked when they were added
+	for len(pending) > 0 {
+		if _, done := selected[pending[0]]; !done {
+			break
+		}
+		pending = pending[1:]
+	}
+	if len(pending) == 0 {
+		return selected, true
+	}
+	name := pending[0]
+
+	candidates := s.candidates(name, reqs[name])
+	if len(candidates) == 0 {
+		s.fail(len(selected), name, reqs[name])
+		return nil, false
+	}
+
+	for _, v := range candidates {
+		nextSelected := make(map[string]constraint.Version, len(selected)+1)
+		for k, sv := range selected {
+			nextSelected[k] = sv
+		}
+		nextSelected[name] = v
+		nextReqs := make(map[string][]requirement, len(reqs))
+		for k, r := range reqs {
+			nextReqs[k] = r
+		}
+		nextPending := append([]string{}, pending[1:]...)
+
+		// Add the candidate's dependencies, rejecting it if one conflicts with an earlier choice
+		deps := s.universe[name][v.String()]
+		if deps == nil {
+			deps = s.universe[name][shortVersion(v)]
+		}
+		depNames := make([]string, 0, len(deps))
+		for dep := range deps {
+			depNames = append(depNames, dep)
+		}
+		sort.Strings(depNames)
+		consistent := true
+		for _, dep := range depNames {
+			c, err := constraint.Parse(deps[dep])
+			if err != nil {
+				consistent = false
+				break
+			}
+			req := requirement{constraint: c, from: name + " " + v.String()}
+			if chosen, ok := nextSelected[dep]; ok && !c.Check(chosen) {
+				s.fail(len(selected), dep, append(append([]requirement{}, nextReqs[dep]...), req))
+				consistent = false
+				break
+			}
+			nextReqs[dep] = append(append([]requirement{}, nextReqs[dep]...), req)
+			nextPending = append(nextPending, dep)
+		}
+		if !consistent {
+			continue
+		}
+
+		if result, ok := s.solve(nextSelected, nextReqs, nextPending); ok {
+			return result, true
+		}
+		if s.steps > maxSteps {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// candidates returns the versions of a cookbook allowed by its pin and requirements, newest first
+func (s *solver) candidates(name string, reqs []requirement) []constraint.Version {
+	var out []constraint.Version
+	for raw := range s.universe[name] {
+		v, err := constraint.ParseVersion(raw)
+		if err != nil {
+			continue
+		}
+		if pin, ok := s.pins[name]; ok && !pin.Check(v) {
+			continue
+		}
+		allowed := true
+		for _, r := range reqs {
+			if !r.constraint.Check(v) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Compare(out[j]) > 0 })
+	return out
+}
+
+// fail records why a cookbook could not be resolved, keeping the reason from the deepest point of the search
+func (s *solver) fail(depth int, name string, reqs []requirement) {
+	if s.failure != "" && depth < s.depth {
+		return
+	}
+	s.depth = depth
+	if _, ok := s.universe[name]; !ok {
+		s.failure = fmt.Sprintf("cookbook '%s' does not exist", name)
+		return
+	}
+	parts := make([]string, 0, len(reqs)+1)
+	if pin, ok := s.pins[name]; ok {
+		parts = append(parts, fmt.Sprintf("%s (environment)", pin))
+	}
+	for _, r := range reqs {
+		parts = append(parts, fmt.Sprintf("%s (%s)", r.constraint, r.from))
+	}
+	s.failure = fmt.Sprintf("no version of '%s' satisfies %s", name, strings.Join(parts, ", "))
+}
+
+// shortVersion formats x.y.0 as x.y for universes that list two-component versions
+func shortVersion(v constraint.Version) string {
+	return fmt.Sprintf("%d.%d", v[0], v[1])
+}
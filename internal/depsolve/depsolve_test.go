@@ -0,0 +1,155 @@
+package depsolve
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testUniverse: app depends on db; db 2.x needs a newer base than app 1.0 allows
+var testUniverse = Universe{
+	"app": {
+		"1.0.0": {"db": ">= 1.0", "base": "~> 1.0"},
+		"2.0.0": {"db": "~> 2.0"},
+	},
+	"db": {
+		"1.0.0": {"base": ">= 1.0"},
+		"1.5.0": {"base": ">= 1.0"},
+		"2.0.0": {"base": ">= 2.0"},
+		"2.1.0": {"base": ">= 2.0", "missing": ">= 0.0"},
+	},
+	"base": {
+		"1.0.0": nil,
+		"1.2.0": nil,
+		"2.0.0": nil,
+	},
+	"short": {
+		"1.0": nil,
+	},
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		roots   map[string]string
+		pins    map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "newest versions when unconstrained",
+			roots: map[string]string{"app": ""},
+			want:  map[string]string{"app": "2.0.0", "db": "2.0.0", "base": "2.0.0"},
+		},
+		{
+			name:  "environment pin on a root",
+			roots: map[string]string{"app": ""},
+			pins:  map[string]string{"app": "= 1.0.0"},
+			want:  map[string]string{"app": "1.0.0", "db": "1.5.0", "base": "1.2.0"},
+		},
+		{
+			name:  "pin on a dependency backtracks to an older root",
+			roots: map[string]string{"app": ""},
+			pins:  map[string]string{"base": "< 2.0"},
+			want:  map[string]string{"app": "1.0.0", "db": "1.5.0", "base": "1.2.0"},
+		},
+		{
+			name:  "run list version pin",
+			roots: map[string]string{"db": "= 1.0.0"},
+			want:  map[string]string{"db": "1.0.0", "base": "2.0.0"},
+		},
+		{
+			name:  "two component versions in the universe",
+			roots: map[string]string{"short": "~> 1.0"},
+			want:  map[string]string{"short": "1.0.0"},
+		},
+		{
+			name:    "unsatisfiable pin",
+			roots:   map[string]string{"app": ""},
+			pins:    map[string]string{"db": "= 2.0.0", "base": "< 2.0"},
+			wantErr: "no version of",
+		},
+		{
+			name:    "missing cookbook",
+			roots:   map[string]string{"nope": ""},
+			wantErr: "cookbook 'nope' does not exist",
+		},
+		{
+			name:    "invalid pin",
+			roots:   map[string]string{"app": ""},
+			pins:    map[string]string{"app": "~> x"},
+			wantErr: "environment pin for 'app'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Solve(testUniverse, tt.roots, tt.pins)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Solve() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Solve() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Solve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolveSkipsVersionsWithMissingDependencies(t *testing.T) {
+	got, err := Solve(testUniverse, map[string]string{"db": "~> 2.0"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["db"] != "2.0.0" {
+		t.Errorf("db = %s, want 2.0.0 (2.1.0 depends on a missing cookbook)", got["db"])
+	}
+}
+
+func TestSolveGivesUp(t *testing.T) {
+	// Six cookbooks with ten acceptable versions each, solved before one that can never be
+	// satisfied: the search would have to try every combination
+	universe := Universe{"zzz": {"1.0.0": {"gone": "= 9.9.9"}}}
+	roots := map[string]string{"zzz": ""}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		universe[name] = map[string]map[string]string{}
+		for i := 0; i < 10; i++ {
+			universe[name][fmt.Sprintf("1.%d.0", i)] = nil
+		}
+		roots[name] = ""
+	}
+	if _, err := Solve(universe, roots, nil); !errors.Is(err, ErrGaveUp) {
+		t.Errorf("Solve() error = %v, want ErrGaveUp", err)
+	}
+}
+
+func TestRoots(t *testing.T) {
+	tests := []struct {
+		recipes []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{[]string{"nginx", "nginx::ssl", "app::deploy"}, map[string]string{"nginx": "", "app": ""}, false},
+		{[]string{"nginx::ssl", "nginx@1.2.3"}, map[string]string{"nginx": "= 1.2.3"}, false},
+		{[]string{"nginx@1.2.3", "nginx::ssl"}, map[string]string{"nginx": "= 1.2.3"}, false},
+		{[]string{"nginx@1.2.3", "nginx::ssl@1.2.3"}, map[string]string{"nginx": "= 1.2.3"}, false},
+		{[]string{"nginx@1.2.3", "nginx::ssl@2.0.0"}, nil, true},
+		{[]string{"nginx@latest"}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := Roots(tt.recipes)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Roots(%v) error = %v, wantErr %v", tt.recipes, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Roots(%v) = %v, want %v", tt.recipes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change kinds reported by Compare
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change describes a single difference between two JSON-like documents.
+// Path uses dot notation; list elements are addressed by index (e.g. "run_list.0").
+type Change struct {
+	Path string      `json:"path"`
+	Kind string      `json:"kind"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Compare walks a and b and returns the differences, sorted by path.
+// Paths matching any of the ignore patterns (see Match) are skipped together with their children.
+func Compare(a, b interface{}, ignore []string) []Change {
+	changes := []Change{}
+	walk("", normalize(a), normalize(b), ignore, &changes)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// Match reports whether path is covered by pattern. Patterns are dot separated; a "*"
+// segment matches any single segment and a pattern also covers every path below it.
+func Match(pattern, path string) bool {
+	if pattern == "" {
+		return false
+	}
+	ps := strings.Split(pattern, ".")
+	segs := strings.Split(path, ".")
+	if len(segs) < len(ps) {
+		return false
+	}
+	for i, p := range ps {
+		if p != "*" && p != segs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// StringSet compares two string lists as sets, returning the entries only present in b (added)
+// and only present in a (removed), each in first-seen order.
+func StringSet(a, b []string) (added, removed []string) {
+	inA := make(map[string]bool, len(a))
+	for _, s := range a {
+		inA[s] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	added, removed = []string{}, []string{}
+	for _, s := range b {
+		if !inA[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if !inB[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func walk(path string, a, b interface{}, ignore []string, out *[]Change) {
+	if path != "" && ignored(path, ignore) {
+		return
+	}
+
+	am, aIsMap := a.(map[string]interface{})
+	bm, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := make(map[string]struct{}, len(am)+len(bm))
+		for k := range am {
+			keys[k] = struct{}{}
+		}
+		for k := range bm {
+			keys[k] = struct{}{}
+		}
+		for k := range keys {
+			av, aok := am[k]
+			bv, bok := bm[k]
+			child := join(path, k)
+			switch {
+			case aok && !bok:
+				if !ignored(child, ignore) {
+					*out = append(*out, Change{Path: child, Kind: Removed, Old: av})
+				}
+			case !aok && bok:
+				if !ignored(child, ignore) {
+					*out = append(*out, Change{Path: child, Kind: Added, New: bv})
+				}
+			default:
+				walk(child, av, bv, ignore, out)
+			}
+		}
+		return
+	}
+
+	al, aIsList := a.([]interface{})
+	bl, bIsList := b.([]interface{})
+	if aIsList && bIsList {
+		n := len(al)
+		if len(bl) > n {
+			n = len(bl)
+		}
+		for i := 0; i < n; i++ {
+			child := join(path, fmt.Sprintf("%d", i))
+			switch {
+			case i >= len(bl):
+				if !ignored(child, ignore) {
+					*out = append(*out, Change{Path: child, Kind: Removed, Old: al[i]})
+				}
+			case i >= len(al):
+				if !ignored(child, ignore) {
+					*out = append(*out, Change{Path: child, Kind: Added, New: bl[i]})
+				}
+			default:
+				walk(child, al[i], bl[i], ignore, out)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*out = append(*out, Change{Path: path, Kind: Changed, Old: a, New: b})
+	}
+}
+
+func ignored(path string, ignore []string) bool {
+	for _, p := range ignore {
+		if Match(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// normalize round-trips typed values through JSON so that structs, typed maps and
+// slices compare the same way as decoded JSON documents.
+func normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return v
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   interface{}
+		ignore []string
+		want   []Change
+	}{
+		{
+			name: "equal documents",
+			a:    map[string]interface{}{"a": 1, "b": []interface{}{"x"}},
+			b:    map[string]interface{}{"a": 1, "b": []interface{}{"x"}},
+			want: []Change{},
+		},
+		{
+			name: "added and removed keys",
+			a:    map[string]interface{}{"keep": "v", "gone": "old"},
+			b:    map[string]interface{}{"keep": "v", "new": "fresh"},
+			want: []Change{
+				{Path: "gone", Kind: Removed, Old: "old"},
+				{Path: "new", Kind: Added, New: "fresh"},
+			},
+		},
+		{
+			name: "nested maps",
+			a:    map[string]interface{}{"nginx": map[string]interface{}{"port": 80, "ssl": map[string]interface{}{"enabled": false}}},
+			b:    map[string]interface{}{"nginx": map[string]interface{}{"port": 8080, "ssl": map[string]interface{}{"enabled": false, "cert": "/etc/cert"}}},
+			want: []Change{
+				{Path: "nginx.port", Kind: Changed, Old: float64(80), New: float64(8080)},
+				{Path: "nginx.ssl.cert", Kind: Added, New: "/etc/cert"},
+			},
+		},
+		{
+			name: "arrays compared by index",
+			a:    map[string]interface{}{"run_list": []string{"recipe[a]", "recipe[b]", "recipe[c]"}},
+			b:    map[string]interface{}{"run_list": []string{"recipe[a]", "role[web]"}},
+			want: []Change{
+				{Path: "run_list.1", Kind: Changed, Old: "recipe[b]", New: "role[web]"},
+				{Path: "run_list.2", Kind: Removed, Old: "recipe[c]"},
+			},
+		},
+		{
+			name: "array grows",
+			a:    []interface{}{1},
+			b:    []interface{}{1, map[string]interface{}{"k": "v"}},
+			want: []Change{
+				{Path: "1", Kind: Added, New: map[string]interface{}{"k": "v"}},
+			},
+		},
+		{
+			name: "type changes",
+			a:    map[string]interface{}{"port": "80", "hosts": []interface{}{"a"}, "opts": map[string]interface{}{"x": 1}},
+			b:    map[string]interface{}{"port": 80, "hosts": "a", "opts": []interface{}{"x"}},
+			want: []Change{
+				{Path: "hosts", Kind: Changed, Old: []interface{}{"a"}, New: "a"},
+				{Path: "opts", Kind: Changed, Old: map[string]interface{}{"x": float64(1)}, New: []interface{}{"x"}},
+				{Path: "port", Kind: Changed, Old: "80", New: float64(80)},
+			},
+		},
+		{
+			name: "null versus value",
+			a:    map[string]interface{}{"v": nil},
+			b:    map[string]interface{}{"v": false},
+			want: []Change{
+				{Path: "v", Kind: Changed, Old: nil, New: false},
+			},
+		},
+		{
+			name:   "ignored paths and their children",
+			a:      map[string]interface{}{"ohai_time": 1, "cpu": map[string]interface{}{"0": map[string]interface{}{"mhz": 1000, "model": "a"}}},
+			b:      map[string]interface{}{"ohai_time": 2, "cpu": map[string]interface{}{"0": map[string]interface{}{"mhz": 2000, "model": "b"}, "1": map[string]interface{}{"mhz": 1}}},
+			ignore: []string{"ohai_time", "cpu.*.mhz", "cpu.1"},
+			want: []Change{
+				{Path: "cpu.0.model", Kind: Changed, Old: "a", New: "b"},
+			},
+		},
+		{
+			name: "structs normalized through JSON",
+			a: struct {
+				Name string `json:"name"`
+			}{"web1"},
+			b:    map[string]interface{}{"name": "web2"},
+			want: []Change{{Path: "name", Kind: Changed, Old: "web1", New: "web2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.a, tt.b, tt.ignore)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"memory.free", "memory.free", true},
+		{"memory", "memory.free", true},
+		{"memory.free", "memory", false},
+		{"cpu.*.mhz", "cpu.3.mhz", true},
+		{"cpu.*.mhz", "cpu.3.model", false},
+		{"", "anything", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestStringSet(t *testing.T) {
+	added, removed := StringSet([]string{"a", "b", "c"}, []string{"c", "d", "a", "e"})
+	if !reflect.DeepEqual(added, []string{"d", "e"}) {
+		t.Errorf("added = %v", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"b"}) {
+		t.Errorf("removed = %v", removed)
+	}
+}
@@ -0,0 +1,170 @@
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Actions recorded with each entry
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// idPattern matches entry ids, which double as file names
+var idPattern = regexp.MustCompile(`^[0-9]{8}T[0-9]{6}\.[0-9]{9}Z-[0-9a-f]{8}$`)
+
+// Entry is a snapshot of an object taken just before the server changed it
+type Entry struct {
+	ID           string          `json:"id"`
+	Time         time.Time       `json:"time"`
+	Tool         string          `json:"tool"`
+	Caller       string          `json:"caller"`
+	User         string          `json:"user"`
+	Organization string          `json:"organization"`
+	Kind         string          `json:"kind"`
+	Name         string          `json:"name" jsonschema:"Object name; data bag items use bag/item"`
+	Action       string          `json:"action"`
+	Existed      bool            `json:"existed" jsonschema:"Whether the object existed before the change; only then is a snapshot stored"`
+	Object       json.RawMessage `json:"object,omitempty"`
+}
+
+// Filter selects entries returned by List; empty fields match everything
+type Filter struct {
+	Organization string
+	Kind         string
+	Name         string
+	Limit        int
+}
+
+// Journal stores entries as JSON files under <dir>/<organization>/<id>.json
+type Journal struct {
+	dir string
+}
+
+// New opens a journal directory, creating it if needed
+func New(dir string) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create journal directory: %w", err)
+	}
+	return &Journal{dir: dir}, nil
+}
+
+// Dir returns the journal directory
+func (j *Journal) Dir() string {
+	return j.dir
+}
+
+// Record assigns the entry an id and time and writes it to disk
+func (j *Journal) Record(e Entry) (Entry, error) {
+	if !validSegment(e.Organization) {
+		return Entry{}, fmt.Errorf("invalid organization '%s'", e.Organization)
+	}
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return Entry{}, err
+	}
+	e.Time = time.Now().UTC()
+	e.ID = e.Time.Format("20060102T150405.000000000Z") + "-" + hex.EncodeToString(b)
+
+	orgDir := filepath.Join(j.dir, e.Organization)
+	if err := os.MkdirAll(orgDir, 0o700); err != nil {
+		return Entry{}, fmt.Errorf("create journal directory: %w", err)
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := os.WriteFile(filepath.Join(orgDir, e.ID+".json"), data, 0o600); err != nil {
+		return Entry{}, fmt.Errorf("write journal entry: %w", err)
+	}
+	return e, nil
+}
+
+// Get reads a single entry
+func (j *Journal) Get(organization, id string) (Entry, error) {
+	if !validSegment(organization) || !idPattern.MatchString(id) {
+		return Entry{}, fmt.Errorf("invalid journal entry '%s/%s'", organization, id)
+	}
+	data, err := os.ReadFile(filepath.Join(j.dir, organization, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Entry{}, fmt.Errorf("journal entry '%s' not found in organization '%s'", id, organization)
+		}
+		return Entry{}, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, fmt.Errorf("decode journal entry '%s': %w", id, err)
+	}
+	return e, nil
+}
+
+// List returns matching entries, newest first, without their object snapshots
+func (j *Journal) List(f Filter) ([]Entry, error) {
+	orgs := []string{f.Organization}
+	if f.Organization == "" {
+		dirs, err := os.ReadDir(j.dir)
+		if err != nil {
+			return nil, err
+		}
+		orgs = orgs[:0]
+		for _, d := range dirs {
+			if d.IsDir() {
+				orgs = append(orgs, d.Name())
+			}
+		}
+	}
+
+	var ids []struct{ org, id string }
+	for _, org := range orgs {
+		if !validSegment(org) {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(j.dir, org))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, file := range files {
+			if id := strings.TrimSuffix(file.Name(), ".json"); idPattern.MatchString(id) {
+				ids = append(ids, struct{ org, id string }{org, id})
+			}
+		}
+	}
+	// Ids start with a sortable timestamp
+	sort.Slice(ids, func(a, b int) bool { return ids[a].id > ids[b].id })
+
+	entries := []Entry{}
+	for _, ref := range ids {
+		e, err := j.Get(ref.org, ref.id)
+		if err != nil {
+			return nil, err
+		}
+		if (f.Kind != "" && e.Kind != f.Kind) || (f.Name != "" && e.Name != f.Name) {
+			continue
+		}
+		e.Object = nil
+		entries = append(entries, e)
+		if f.Limit > 0 && len(entries) == f.Limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// validSegment guards path segments built from organization names
+func validSegment(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `/\`)
+}
@@ -0,0 +1,144 @@
+package journal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndGet(t *testing.T) {
+	dir := t.TempDir()
+	j, err := New(filepath.Join(dir, "journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := j.Record(Entry{
+		Tool:         "updateRole",
+		Caller:       "test",
+		User:         "admin",
+		Organization: "acme",
+		Kind:         "role",
+		Name:         "web",
+		Action:       ActionUpdate,
+		Existed:      true,
+		Object:       json.RawMessage(`{"name":"web"}`),
+	})
+	if err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	if !idPattern.MatchString(e.ID) || e.Time.IsZero() {
+		t.Fatalf("Record() did not assign an id and time: %+v", e)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "journal", "acme", e.ID+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("entry file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	got, err := j.Get("acme", e.ID)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if got.Name != "web" || got.Action != ActionUpdate || !got.Existed {
+		t.Errorf("Get() = %+v", got)
+	}
+	var object map[string]string
+	if err := json.Unmarshal(got.Object, &object); err != nil || object["name"] != "web" {
+		t.Errorf("Get() object = %s (%v)", got.Object, err)
+	}
+
+	if _, err := j.Get("other", e.ID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Get() from another organization: error = %v, want not found", err)
+	}
+}
+
+func TestRejectsPathTraversal(t *testing.T) {
+	j, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, org := range []string{"", ".", "..", "../acme", `a\b`} {
+		if _, err := j.Record(Entry{Organization: org}); err == nil {
+			t.Errorf("Record() accepted organization %q", org)
+		}
+	}
+
+	e, err := j.Record(Entry{Organization: "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []struct{ org, id string }{
+		{"..", e.ID},
+		{"acme", "../acme/" + e.ID},
+		{"acme", e.ID + ".json"},
+		{"acme", "latest"},
+	} {
+		if _, err := j.Get(ref.org, ref.id); err == nil || !strings.Contains(err.Error(), "invalid journal entry") {
+			t.Errorf("Get(%q, %q): error = %v, want invalid journal entry", ref.org, ref.id, err)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	j, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := func(org, kind, name string) Entry {
+		t.Helper()
+		e, err := j.Record(Entry{Organization: org, Kind: kind, Name: name, Action: ActionUpdate, Existed: true, Object: json.RawMessage(`{}`)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	first := record("acme", "node", "web1")
+	second := record("acme", "role", "web")
+	third := record("globex", "node", "web1")
+
+	// Stray files are not entries
+	if err := os.WriteFile(filepath.Join(dir, "acme", "notes.txt"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(entries []Entry) []string {
+		out := make([]string, 0, len(entries))
+		for _, e := range entries {
+			if e.Object != nil {
+				t.Errorf("List() returned the snapshot of %s", e.ID)
+			}
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"everything newest first", Filter{}, []string{third.ID, second.ID, first.ID}},
+		{"organization", Filter{Organization: "acme"}, []string{second.ID, first.ID}},
+		{"kind", Filter{Kind: "node"}, []string{third.ID, first.ID}},
+		{"name within organization", Filter{Organization: "acme", Name: "web1"}, []string{first.ID}},
+		{"limit", Filter{Limit: 2}, []string{third.ID, second.ID}},
+		{"unknown organization", Filter{Organization: "initech"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := j.List(tt.filter)
+			if err != nil {
+				t.Fatalf("List() error: %v", err)
+			}
+			if got := ids(entries); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
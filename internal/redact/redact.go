@@ -0,0 +1,255 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultKeyPatterns match attribute and field names whose values are always redacted
+var DefaultKeyPatterns = []string{
+	`(?i)(password|passwd|passphrase|secret|token|api_?key|private_?key|access_?key|credentials?)$`,
+}
+
+// Detectors recognise secret values regardless of where they appear
+var Detectors = map[string]*regexp.Regexp{
+	"pem": regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----`),
+	"aws": regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`),
+	"jwt": regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+}
+
+// Options configure an Engine; nil slices select the defaults
+type Options struct {
+	KeyPatterns []string // Regular expressions matched against each field name
+	Paths       []string // Dot paths to redact; "*" matches one segment, "**" any number of segments
+	Detectors   []string // Names from Detectors; defaults to all
+}
+
+// Engine replaces secret values in JSON documents with a marker naming the redacted path
+type Engine struct {
+	keys      []*regexp.Regexp
+	paths     [][]string
+	detectors []*regexp.Regexp
+}
+
+// New compiles the options into an Engine
+func New(opts Options) (*Engine, error) {
+	e := &Engine{}
+	patterns := opts.KeyPatterns
+	if patterns == nil {
+		patterns = DefaultKeyPatterns
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction key pattern '%s': %w", p, err)
+		}
+		e.keys = append(e.keys, re)
+	}
+	for _, p := range opts.Paths {
+		e.paths = append(e.paths, strings.Split(p, "."))
+	}
+	names := opts.Detectors
+	if names == nil {
+		for name := range Detectors {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		re, ok := Detectors[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction detector '%s'", name)
+		}
+		e.detectors = append(e.detectors, re)
+	}
+	return e, nil
+}
+
+// markerPrefix starts every redaction placeholder, including the "[REDACTED]" used for
+// undecrypted data bag values
+const markerPrefix = "[REDACTED"
+
+// Marker is the replacement value for a redacted path
+func Marker(path string) string {
+	return markerPrefix + ":" + path + "]"
+}
+
+// FindMarker returns the dot path of the first string value in a decoded JSON value that holds a
+// redaction placeholder. Writers use it to refuse documents that were read back in redacted form.
+func FindMarker(v interface{}) (path string, found bool) {
+	return findMarker(nil, v)
+}
+
+func findMarker(path []string, v interface{}) (string, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if p, ok := findMarker(append(path[:len(path):len(path)], k), t[k]); ok {
+				return p, true
+			}
+		}
+	case []interface{}:
+		for i, child := range t {
+			if p, ok := findMarker(append(path[:len(path):len(path)], strconv.Itoa(i)), child); ok {
+				return p, true
+			}
+		}
+	case string:
+		if strings.Contains(t, markerPrefix) {
+			return strings.Join(path, "."), true
+		}
+	}
+	return "", false
+}
+
+// Redact returns a copy of a decoded JSON value with secret values replaced by Marker(path)
+func (e *Engine) Redact(v interface{}) interface{} {
+	return e.walk(nil, v)
+}
+
+// RedactJSON redacts a JSON document, returning the re-encoded document
+func (e *Engine) RedactJSON(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(e.Redact(v))
+}
+
+// RedactText redacts a block of tool output text. JSON documents are redacted as by RedactJSON;
+// any other text that contains a detected secret is replaced entirely by Marker("text").
+func (e *Engine) RedactText(s string) string {
+	if json.Valid([]byte(s)) {
+		if b, err := e.RedactJSON([]byte(s)); err == nil {
+			return string(b)
+		}
+	}
+	for _, re := range e.detectors {
+		if re.MatchString(s) {
+			return Marker("text")
+		}
+	}
+	return s
+}
+
+func (e *Engine) walk(path []string, v interface{}) interface{} {
+	if len(path) > 0 && (e.matchKey(path[len(path)-1]) || e.matchPath(path)) {
+		if v == nil {
+			return nil
+		}
+		return Marker(strings.Join(path, "."))
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if isChange(t) {
+			return e.walkChange(path, t)
+		}
+		out := make(map[string]interface{}, len(t))
+		for k, child := range t {
+			out[k] = e.walk(append(path[:len(path):len(path)], k), child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, child := range t {
+			out[i] = e.walk(append(path[:len(path):len(path)], strconv.Itoa(i)), child)
+		}
+		return out
+	case string:
+		for _, re := range e.detectors {
+			if re.MatchString(t) {
+				return Marker(strings.Join(path, "."))
+			}
+		}
+	}
+	return v
+}
+
+// isChange recognises a diff entry ({"path", "kind", "old", "new"}), whose values live at the
+// attribute path it names rather than under its own "old" and "new" keys
+func isChange(m map[string]interface{}) bool {
+	_, hasPath := m["path"].(string)
+	_, hasKind := m["kind"].(string)
+	return hasPath && hasKind
+}
+
+// walkChange redacts the old and new values of a diff entry as if they were found at the entry's
+// path, so a changed password is caught by its attribute name
+func (e *Engine) walkChange(path []string, change map[string]interface{}) map[string]interface{} {
+	var at []string
+	if p := change["path"].(string); p != "" {
+		at = strings.Split(p, ".")
+	}
+	secret := false
+	for i := range at {
+		if e.matchKey(at[i]) || e.matchPath(at[:i+1]) {
+			secret = true
+			break
+		}
+	}
+	out := make(map[string]interface{}, len(change))
+	for k, child := range change {
+		switch {
+		case k != "old" && k != "new":
+			out[k] = e.walk(append(path[:len(path):len(path)], k), child)
+		case secret && child != nil:
+			out[k] = Marker(strings.Join(at, "."))
+		default:
+			out[k] = e.walk(at, child)
+		}
+	}
+	return out
+}
+
+func (e *Engine) matchKey(key string) bool {
+	// List indices are never secret names
+	if _, err := strconv.Atoi(key); err == nil {
+		return false
+	}
+	for _, re := range e.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) matchPath(path []string) bool {
+	for _, p := range e.paths {
+		if matchSegments(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches a full path against a pattern where "*" matches one segment
+// and "**" matches zero or more segments
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if pattern[0] != "*" && pattern[0] != path[0] {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}